@@ -5,6 +5,7 @@ go 1.19
 replace github.com/gogo/protobuf => github.com/regen-network/protobuf v1.3.3-alpha.regen.1
 
 require (
+	cosmoshub/client v0.0.0-00010101000000-000000000000
 	github.com/cosmos/cosmos-sdk v0.46.2
 	google.golang.org/grpc v1.49.0
 )
@@ -107,3 +108,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	sigs.k8s.io/yaml v1.3.0 // indirect
 )
+
+replace cosmoshub/client => ../client
@@ -2,118 +2,129 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"time"
+	"os"
+
+	"cosmoshub/client"
 
-	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/authz"
-	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// The grants are signed transactions, they are sent with submit-transaction:
+//
+//	submit-transaction authz grant -from <granter key> -grantee <address> -type redelegate -allow <validator>
+//	submit-transaction authz revoke -from <granter key> -grantee <address> -type redelegate
+//	submit-transaction authz exec -from <grantee key> -granter <address> -type redelegate ...
 func main() {
-	// Create GRPC connection
-	mainNet := "rpc.sentry-01.theta-testnet.polypore.xyz:9090" // testnet // "54.180.225.240:9090" cosmos mainnet
-	grpcConn, err := grpc.Dial(
-		mainNet,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec.NewProtoCodec(nil).GRPCCodec())),
-	)
-	if err != nil {
-		log.Fatalf("grpcConn Error %s", err)
+	// testnet, "54.180.225.240:9090" cosmos mainnet
+	grpcURL := flag.String("grpc", "rpc.sentry-01.theta-testnet.polypore.xyz:9090", "gRPC endpoint")
+	granter := flag.String("granter", "cosmos19kzdcmysekqu926fwdcjg5pdqlx3saujcldys5", "bech32 address of the granter, empty to query only by grantee")
+	grantee := flag.String("grantee", "cosmos1c28cfmvvne62n5347h3nptar7ka0dffxc0nd8z", "bech32 address of the grantee, empty to query only by granter")
+	msgType := flag.String("msg-type", "", "message type URL of the grants between granter and grantee, all when empty")
+	limit := flag.Uint64("limit", 0, "max grants of each query, 0 is all of them")
+	offset := flag.Uint64("offset", 0, "grants skipped from the start of each query")
+	reverse := flag.Bool("reverse", false, "list the grants in descending order")
+	flag.Parse()
+	// the queries request all the pages, the node truncates each one to its page size
+	page := []client.PageOption{client.PageLimit(*limit), client.PageOffset(*offset), client.PageReverse(*reverse)}
+
+	if err := run(*grpcURL, *granter, *grantee, *msgType, page); err != nil {
+		log.Printf("Error %s", err)
+		os.Exit(client.ExitCode(err)) // same exit codes as submit-transaction
 	}
-	defer grpcConn.Close()
-
-	// addresses
-	granter := "cosmos19kzdcmysekqu926fwdcjg5pdqlx3saujcldys5"
-	grantee := "cosmos1c28cfmvvne62n5347h3nptar7ka0dffxc0nd8z"
-	validator := "cosmosvaloper1c28cfmvvne62n5347h3nptar7ka0dffxam8ct3"
-
-	// Check granted Authorizations
-	checkGrants(grpcConn, granter)
-
-	// Enable grant(if already granted it will be overwritten)
-	grant(grpcConn, granter, grantee, validator)
-
-	// Check granted Authorizations(again)
-	checkGrants(grpcConn, granter)
 }
 
-func checkGrants(grpcConn *grpc.ClientConn, address string) {
-	// Create the query client
-	queryClient := authz.NewQueryClient(grpcConn)
-
-	// Create the query
-	request := authz.QueryGranterGrantsRequest{Granter: address}
-	fmt.Println("QueryGrantsRequest", request)
-
-	// Query grants
-	response, err := queryClient.GranterGrants(context.Background(), &request)
+func run(grpcURL string, granter string, grantee string, msgType string, page []client.PageOption) error {
+	// Create GRPC connection
+	c, err := client.Dial(grpcURL)
 	if err != nil {
-		log.Fatalf("GranterGrants Error %s", err)
+		return err
 	}
+	defer c.Close()
 
-	// Print grants
-	if len(response.Grants) == 0 {
-		fmt.Println("No grants for", address)
-	} else {
-		for _, grant := range response.Grants {
-			fmt.Println("Grant", grant.Expiration, grant.Authorization.GetTypeUrl(), grant.Granter, grant.Grantee)
+	var granterAddress, granteeAddress types.AccAddress
+	if granter != "" {
+		if granterAddress, err = types.AccAddressFromBech32(granter); err != nil {
+			return fmt.Errorf("granter %s: %w", granter, err)
+		}
+	}
+	if grantee != "" {
+		if granteeAddress, err = types.AccAddressFromBech32(grantee); err != nil {
+			return fmt.Errorf("grantee %s: %w", grantee, err)
 		}
 	}
-}
-
-func grant(grpcConn *grpc.ClientConn, granter string, grantee string, validator string) {
-	// Create the MsgClient
-	msgClient := authz.NewMsgClient(grpcConn)
 
-	// Create the MsgGrant
-	var tokens *types.Coin // empty means no limit https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#Coin
-	valAddress, err := types.ValAddressFromBech32(validator)
-	if err != nil {
-		log.Fatalf("ValAddressFromBech32 Error %s", err)
+	// Check granted Authorizations
+	if granterAddress != nil {
+		if err := checkGranterGrants(c, granterAddress, page); err != nil {
+			return err
+		}
 	}
-	allowed := []types.ValAddress{valAddress}
-	denied := []types.ValAddress{}
-	stakeAuth, err := stakingtypes.NewStakeAuthorization(allowed, denied, stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_REDELEGATE, tokens) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/staking/types#NewStakeAuthorization https://github.com/cosmos/cosmos-sdk/blob/v0.46.0-rc1/x/staking/types/authz.go#L15-L35
-	if err != nil {
-		log.Fatalf("stakingtypes.NewStakeAuthorization Error %s", err)
+	if granteeAddress != nil {
+		if err := checkGranteeGrants(c, granteeAddress, page); err != nil {
+			return err
+		}
 	}
-	granterAddr, err := types.AccAddressFromBech32(granter)
-	if err != nil {
-		log.Fatalf("GranterAddr Error %s", err)
+	if granterAddress != nil && granteeAddress != nil {
+		return checkGrants(c, granterAddress, granteeAddress, msgType, page)
 	}
-	granteeAddr, err := types.AccAddressFromBech32(grantee)
+	return nil
+}
+
+// grants given by the granter
+func checkGranterGrants(c *client.Client, granter types.AccAddress, page []client.PageOption) error {
+	grants, err := c.GranterGrants(context.Background(), granter, page...)
 	if err != nil {
-		log.Fatalf("GranteeAddr Error %s", err)
+		return err
 	}
-	expiration := time.Now().Add(time.Hour * 24 * 30)
-	msgGrant, err := authz.NewMsgGrant(granterAddr, granteeAddr, stakeAuth, &expiration) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/authz#NewMsgGrant https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/authz/spec/03_messages.md#msggrant
+	fmt.Println("Grants given by", granter.String())
+	return printGrants(c, grants)
+}
+
+// grants received by the grantee
+func checkGranteeGrants(c *client.Client, grantee types.AccAddress, page []client.PageOption) error {
+	grants, err := c.GranteeGrants(context.Background(), grantee, page...)
 	if err != nil {
-		log.Fatalf("NewMsgGrant Error %s", err)
+		return err
 	}
+	fmt.Println("Grants received by", grantee.String())
+	return printGrants(c, grants)
+}
 
-	// Print data
-	fmt.Println("Tokens", tokens)
-	fmt.Println("Expiration", expiration)
-	fmt.Println("Allowed addresses", valAddress.String())
-	fmt.Println("Denied addresses", denied)
-	fmt.Println("Granter address", granterAddr.String())
-	fmt.Println("Grantee address", granteeAddr.String())
-
-	// Run Grant
-	//TODO: review discord https://discord.com/channels/669268347736686612/1019978171367559208
-	//TODO: is needed to have stake before(I don't think, only a grant).
-	//TODO: val address is needed? or is needed the default address?
-	//TODO: is not impl in testnet?
-	response, err := msgClient.Grant(context.Background(), msgGrant, grpc.FailFastCallOption{FailFast: false})
+// grants between the granter and the grantee, only the msgType one when it is not empty
+func checkGrants(c *client.Client, granter types.AccAddress, grantee types.AccAddress, msgType string, page []client.PageOption) error {
+	grants, err := c.Grants(context.Background(), granter, grantee, msgType, page...)
 	if err != nil {
-		log.Fatalf("MsgClient.Grant Error %s", err)
+		return err
+	}
+	fmt.Println("Grants from", granter.String(), "to", grantee.String())
+	var pairGrants []*authz.GrantAuthorization
+	for _, grant := range grants {
+		pairGrants = append(pairGrants, &authz.GrantAuthorization{
+			Granter:       granter.String(),
+			Grantee:       grantee.String(),
+			Authorization: grant.Authorization,
+			Expiration:    grant.Expiration,
+		})
 	}
+	return printGrants(c, pairGrants)
+}
 
-	// Print the response(empty struct if success)
-	fmt.Println("Grant success", response)
+// Print the unpacked authorizations: GenericAuthorization, SendAuthorization or StakeAuthorization
+func printGrants(c *client.Client, grants []*authz.GrantAuthorization) error {
+	if len(grants) == 0 {
+		fmt.Println(" No grants")
+		return nil
+	}
+	for _, grant := range grants {
+		authorization, err := c.UnpackAuthorization(grant.Authorization)
+		if err != nil {
+			return err
+		}
+		fmt.Println(" Grant", grant.Granter, "->", grant.Grantee, client.DescribeAuthorization(authorization), "expiration", grant.Expiration)
+	}
+	return nil
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/fakenode"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+var (
+	testGranter = types.AccAddress([]byte("granter_____________"))
+	testGrantee = types.AccAddress([]byte("grantee_____________"))
+)
+
+// the output printed to stdout while f runs
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe error %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read stdout error %v", err)
+	}
+	return string(out)
+}
+
+func TestCheckGrants(t *testing.T) {
+	node := fakenode.Start("test-chain")
+	defer node.Close()
+	c, err := node.Dial()
+	if err != nil {
+		t.Fatalf("Dial error %v", err)
+	}
+	defer c.Close()
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	node.AddGrant(testGranter, testGrantee, banktypes.NewSendAuthorization(types.NewCoins(types.NewInt64Coin("uatom", 100))), &expiration)
+	node.AddGrant(testGranter, testGrantee, authz.NewGenericAuthorization("/cosmos.gov.v1beta1.MsgVote"), nil)
+	node.AddGrant(testGranter, types.AccAddress([]byte("other_______________")), authz.NewGenericAuthorization("/cosmos.gov.v1beta1.MsgVote"), nil)
+
+	sut := captureStdout(t, func() {
+		if err := checkGrants(c, testGranter, testGrantee, "", []client.PageOption{client.PageLimit(1)}); err != nil {
+			t.Errorf("checkGrants error %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSpace(sut), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "send spend limit 100uatom") {
+		t.Errorf("checkGrants should print the first grant of the pair limited to 1 but prints %q", sut)
+	}
+
+	sut = captureStdout(t, func() {
+		if err := checkGrants(c, testGranter, testGrantee, "/cosmos.gov.v1beta1.MsgVote", nil); err != nil {
+			t.Errorf("checkGrants error %v", err)
+		}
+	})
+
+	if !strings.Contains(sut, "generic /cosmos.gov.v1beta1.MsgVote expiration <nil>") || strings.Contains(sut, "send") {
+		t.Errorf("checkGrants should print only the vote grant but prints %q", sut)
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// Bech32Prefixes of a chain, the account prefix is listed as bech32_prefix in the chain registry
+// https://github.com/cosmos/chain-registry/blob/master/osmosis/chain.json
+// https://docs.cosmos.network/v0.46/basics/accounts.html#addresses
+type Bech32Prefixes struct {
+	Account   string `yaml:"account"`   // ie: cosmos
+	Validator string `yaml:"validator"` // ie: cosmosvaloper
+	Consensus string `yaml:"consensus"` // ie: cosmosvalcons
+}
+
+// CosmosPrefixes are the cosmoshub prefixes, used by default
+var CosmosPrefixes = NewBech32Prefixes(sdk.Bech32MainPrefix)
+
+// NewBech32Prefixes returns the prefixes of a chain following the SDK convention: <account>valoper and <account>valcons
+func NewBech32Prefixes(account string) Bech32Prefixes {
+	return Bech32Prefixes{
+		Account:   account,
+		Validator: account + sdk.PrefixValidator + sdk.PrefixOperator,
+		Consensus: account + sdk.PrefixValidator + sdk.PrefixConsensus,
+	}
+}
+
+// WithDefaults fills the empty validator and consensus prefixes from the account prefix
+func (p Bech32Prefixes) WithDefaults() Bech32Prefixes {
+	if p.Account == "" {
+		p.Account = sdk.Bech32MainPrefix
+	}
+	defaults := NewBech32Prefixes(p.Account)
+	if p.Validator == "" {
+		p.Validator = defaults.Validator
+	}
+	if p.Consensus == "" {
+		p.Consensus = defaults.Consensus
+	}
+	return p
+}
+
+// SetGlobal sets the prefixes in the SDK global config. The SDK messages use it to encode and decode the
+// addresses(ie: MsgSend.GetSigners), so it must be set before building transactions for a chain that is not cosmoshub.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#Config
+func (p Bech32Prefixes) SetGlobal() {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount(p.Account, p.Account+sdk.PrefixPublic)
+	config.SetBech32PrefixForValidator(p.Validator, p.Validator+sdk.PrefixPublic)
+	config.SetBech32PrefixForConsensusNode(p.Consensus, p.Consensus+sdk.PrefixPublic)
+}
+
+// AccAddress encodes an account address with the account prefix
+func (p Bech32Prefixes) AccAddress(address sdk.AccAddress) string {
+	return mustBech32(p.Account, address)
+}
+
+// ValAddress encodes a validator operator address with the validator prefix
+func (p Bech32Prefixes) ValAddress(address sdk.ValAddress) string {
+	return mustBech32(p.Validator, address)
+}
+
+// ConsAddress encodes a validator consensus address with the consensus prefix
+func (p Bech32Prefixes) ConsAddress(address sdk.ConsAddress) string {
+	return mustBech32(p.Consensus, address)
+}
+
+// ParseAccAddress decodes a bech32 account address, it must use the account prefix
+func (p Bech32Prefixes) ParseAccAddress(address string) (sdk.AccAddress, error) {
+	bz, err := sdk.GetFromBech32(address, p.Account) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#GetFromBech32
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(bz), sdk.VerifyAddressFormat(bz)
+}
+
+// ParseValAddress decodes a bech32 validator address, it must use the validator prefix
+func (p Bech32Prefixes) ParseValAddress(address string) (sdk.ValAddress, error) {
+	bz, err := sdk.GetFromBech32(address, p.Validator)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ValAddress(bz), sdk.VerifyAddressFormat(bz)
+}
+
+// DecodeAddress decodes a bech32 address with any prefix or a hex address(with or without 0x), it returns the
+// prefix(empty for hex) and the address bytes
+func DecodeAddress(address string) (string, []byte, error) {
+	if hexAddress := strings.TrimPrefix(strings.ToLower(address), "0x"); len(hexAddress) == 2*20 || len(hexAddress) == 2*32 {
+		if bz, err := hex.DecodeString(hexAddress); err == nil {
+			return "", bz, nil
+		}
+	}
+	prefix, bz, err := bech32.DecodeAndConvert(address) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types/bech32
+	if err != nil {
+		return "", nil, fmt.Errorf("decode address %s: %w", address, err)
+	}
+	return prefix, bz, nil
+}
+
+// ConvertAddress re-encodes a bech32 or hex address with another prefix
+func ConvertAddress(address string, prefix string) (string, error) {
+	_, bz, err := DecodeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return bech32.ConvertAndEncode(prefix, bz)
+}
+
+// the address bytes are always valid 5-bit groups, encoding only fails with an empty or invalid prefix
+func mustBech32(prefix string, bz []byte) string {
+	if len(bz) == 0 {
+		return ""
+	}
+	encoded, err := bech32.ConvertAndEncode(prefix, bz)
+	if err != nil {
+		panic(fmt.Errorf("bech32 prefix %q: %w", prefix, err))
+	}
+	return encoded
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// GranteeGrants returns the authorizations granted to grantee
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/authz#QueryClient
+func (c *Client) GranteeGrants(ctx context.Context, grantee sdk.AccAddress, opts ...PageOption) ([]*authz.GrantAuthorization, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]*authz.GrantAuthorization, *query.PageResponse, error) {
+		res, err := authz.NewQueryClient(c.conn).GranteeGrants(ctx, &authz.QueryGranteeGrantsRequest{Grantee: c.prefixes.AccAddress(grantee), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Grants, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query grantee grants %s: %w", c.prefixes.AccAddress(grantee), err)
+	}
+	return results, nil
+}
+
+// Grants returns the authorizations granted by granter to grantee, all of them when msgTypeURL is empty or the one
+// for the message type(ie: /cosmos.bank.v1beta1.MsgSend)
+func (c *Client) Grants(ctx context.Context, granter sdk.AccAddress, grantee sdk.AccAddress, msgTypeURL string, opts ...PageOption) ([]*authz.Grant, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]*authz.Grant, *query.PageResponse, error) {
+		res, err := authz.NewQueryClient(c.conn).Grants(ctx, &authz.QueryGrantsRequest{
+			Granter:    c.prefixes.AccAddress(granter),
+			Grantee:    c.prefixes.AccAddress(grantee),
+			MsgTypeUrl: msgTypeURL,
+			Pagination: page,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Grants, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query grants %s to %s: %w", c.prefixes.AccAddress(granter), c.prefixes.AccAddress(grantee), err)
+	}
+	return results, nil
+}
+
+// UnpackAuthorization unpacks the Any of a grant into the concrete authorization: GenericAuthorization,
+// SendAuthorization or StakeAuthorization
+func (c *Client) UnpackAuthorization(any *codectypes.Any) (authz.Authorization, error) {
+	var authorization authz.Authorization
+	if err := c.cdc.UnpackAny(any, &authorization); err != nil {
+		return nil, fmt.Errorf("unpack authorization %s: %w", any.GetTypeUrl(), err)
+	}
+	return authorization, nil
+}
+
+// DescribeAuthorization renders the details of the built-in authorizations: the message type of a generic one, the
+// spend limit of a send one and the type, max tokens and validators of a stake one
+func DescribeAuthorization(authorization authz.Authorization) string {
+	switch a := authorization.(type) {
+	case *authz.GenericAuthorization:
+		return fmt.Sprintf("generic %s", a.Msg)
+	case *banktypes.SendAuthorization:
+		return fmt.Sprintf("send spend limit %s", a.SpendLimit.String())
+	case *stakingtypes.StakeAuthorization:
+		maxTokens := "unlimited"
+		if a.MaxTokens != nil {
+			maxTokens = a.MaxTokens.String()
+		}
+		description := fmt.Sprintf("stake %s max tokens %s", a.AuthorizationType, maxTokens)
+		if allow := a.GetAllowList(); allow != nil {
+			description += " allow " + strings.Join(allow.Address, ",")
+		}
+		if deny := a.GetDenyList(); deny != nil {
+			description += " deny " + strings.Join(deny.Address, ",")
+		}
+		return description
+	default:
+		return authorization.MsgTypeURL()
+	}
+}
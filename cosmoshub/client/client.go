@@ -0,0 +1,171 @@
+// Package client queries and broadcasts transactions to a cosmos chain(cosmoshub, gaia) over gRPC.
+// It is shared by the cosmoshub programs, more info on https://docs.cosmos.network/v0.46/run-node/interact-node.html
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Client to a cosmos node. The gRPC connection is used for queries and broadcast and the tendermint RPC
+// endpoint, when it is set, to be notified of the included transactions.
+type Client struct {
+	conn         *grpc.ClientConn
+	cdc          *codec.ProtoCodec
+	rpcURL       string
+	prefixes     Bech32Prefixes
+	pollInterval time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithRPC sets the tendermint RPC endpoint used by WaitForTx to subscribe to the transaction, without it GetTx is polled
+func WithRPC(rpcURL string) Option {
+	return func(c *Client) { c.rpcURL = rpcURL }
+}
+
+// WithPollInterval sets the time between GetTx queries while WaitForTx waits, DefaultPollInterval by default
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) { c.pollInterval = interval }
+}
+
+// WithPrefixes sets the bech32 prefixes used to encode the addresses in the requests, CosmosPrefixes by default
+func WithPrefixes(prefixes Bech32Prefixes) Option {
+	return func(c *Client) { c.prefixes = prefixes.WithDefaults() }
+}
+
+// Dial opens a gRPC connection to grpcURL
+// More info on how to find a host and port on https://github.com/cosmos/chain-registry/blob/master/cosmoshub/chain.json
+func Dial(grpcURL string, opts ...Option) (*Client, error) {
+	conn, err := grpc.Dial( // https://pkg.go.dev/google.golang.org/grpc#Dial
+		grpcURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // The Cosmos SDK doesn't support any transport security mechanism.
+		// the codec unpacks the Any values of the responses(ie: accounts), it needs the registry of their types
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(NewCodec().GRPCCodec())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("grpc.Dial %s: %w", grpcURL, err)
+	}
+	return New(conn, opts...), nil
+}
+
+// New creates a Client using an already opened gRPC connection
+func New(conn *grpc.ClientConn, opts ...Option) *Client {
+	c := &Client{conn: conn, cdc: NewCodec(), prefixes: CosmosPrefixes, pollInterval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Conn returns the underlying gRPC connection to create clients for other modules
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Codec returns the codec used to unpack the query responses
+func (c *Client) Codec() *codec.ProtoCodec {
+	return c.cdc
+}
+
+// Prefixes returns the bech32 prefixes of the chain
+func (c *Client) Prefixes() Bech32Prefixes {
+	return c.prefixes
+}
+
+// Close the gRPC connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetAccount returns the account for an address, it contains the account number and the sequence needed to sign.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/auth/types#QueryClient
+func (c *Client) GetAccount(ctx context.Context, address sdk.AccAddress) (authtypes.AccountI, error) {
+	res, err := authtypes.NewQueryClient(c.conn).Account(ctx, &authtypes.QueryAccountRequest{Address: c.prefixes.AccAddress(address)})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// the account of an address is created by its first transfer
+			err = &statusError{kind: ErrAccountNotFound, err: err}
+		}
+		return nil, fmt.Errorf("query account %s: %w", c.prefixes.AccAddress(address), err)
+	}
+	// Unpack the Any(ie: /cosmos.auth.v1beta1.BaseAccount) into the concrete account type
+	// https://docs.cosmos.network/master/architecture/adr-019-protobuf-state-encoding.html
+	var acc authtypes.AccountI
+	if err := c.cdc.UnpackAny(res.Account, &acc); err != nil {
+		return nil, fmt.Errorf("unpack account %s: %w", res.Account.GetTypeUrl(), err)
+	}
+	return acc, nil
+}
+
+// GetBalance returns the balance of an address for a denom
+func (c *Client) GetBalance(ctx context.Context, address sdk.AccAddress, denom string) (sdk.Coin, error) {
+	res, err := banktypes.NewQueryClient(c.conn).Balance(ctx, &banktypes.QueryBalanceRequest{Address: c.prefixes.AccAddress(address), Denom: denom})
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("query balance %s %s: %w", c.prefixes.AccAddress(address), denom, err)
+	}
+	if res.Balance == nil {
+		return sdk.NewInt64Coin(denom, 0), nil
+	}
+	return *res.Balance, nil
+}
+
+// GetAllBalances returns the balances of an address for all denoms
+func (c *Client) GetAllBalances(ctx context.Context, address sdk.AccAddress, opts ...PageOption) (sdk.Coins, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]sdk.Coin, *query.PageResponse, error) {
+		res, err := banktypes.NewQueryClient(c.conn).AllBalances(ctx, &banktypes.QueryAllBalancesRequest{Address: c.prefixes.AccAddress(address), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Balances, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query all balances %s: %w", c.prefixes.AccAddress(address), err)
+	}
+	return sdk.Coins(results), nil
+}
+
+// GranterGrants returns the authorizations granted by granter
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/authz#QueryClient
+func (c *Client) GranterGrants(ctx context.Context, granter sdk.AccAddress, opts ...PageOption) ([]*authz.GrantAuthorization, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]*authz.GrantAuthorization, *query.PageResponse, error) {
+		res, err := authz.NewQueryClient(c.conn).GranterGrants(ctx, &authz.QueryGranterGrantsRequest{Granter: c.prefixes.AccAddress(granter), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Grants, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query granter grants %s: %w", c.prefixes.AccAddress(granter), err)
+	}
+	return results, nil
+}
+
+// Broadcast the signed transaction bytes in sync mode, the returned response is the result of CheckTx.
+// A response with a Code different than 0 means that the transaction was rejected.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types/tx#ServiceClient
+func (c *Client) Broadcast(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+	res, err := typestx.NewServiceClient(c.conn).BroadcastTx(ctx, &typestx.BroadcastTxRequest{
+		Mode:    typestx.BroadcastMode_BROADCAST_MODE_SYNC, // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types/tx#BroadcastMode
+		TxBytes: txBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("broadcast tx: %w", fromStatus(err))
+	}
+	return res.TxResponse, nil
+}
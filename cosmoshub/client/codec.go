@@ -0,0 +1,41 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/std"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramsproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+// NewInterfaceRegistry returns a registry with the interfaces and implementations needed to unpack the
+// protobuf Any values returned by the queries: accounts, public keys, messages and authorizations.
+// https://docs.cosmos.network/v0.46/core/encoding.html#interface-encoding-and-usage-of-any
+func NewInterfaceRegistry() codectypes.InterfaceRegistry {
+	registry := codectypes.NewInterfaceRegistry() // https://pkg.go.dev/github.com/cosmos/cosmos-sdk@v0.46.0/codec/types#NewInterfaceRegistry
+	std.RegisterInterfaces(registry)              // sdk.Msg, tx and crypto types
+	authtypes.RegisterInterfaces(registry)        // BaseAccount, ModuleAccount
+	vestingtypes.RegisterInterfaces(registry)     // vesting accounts
+	banktypes.RegisterInterfaces(registry)        // MsgSend, SendAuthorization
+	authz.RegisterInterfaces(registry)            // MsgGrant, GenericAuthorization
+	stakingtypes.RegisterInterfaces(registry)     // MsgDelegate, StakeAuthorization
+	distrtypes.RegisterInterfaces(registry)       // MsgWithdrawDelegatorReward
+	feegrant.RegisterInterfaces(registry)         // MsgGrantAllowance, BasicAllowance, PeriodicAllowance
+	govv1beta1.RegisterInterfaces(registry)       // MsgVote, MsgDeposit, TextProposal
+	paramsproposal.RegisterInterfaces(registry)   // ParameterChangeProposal
+	upgradetypes.RegisterInterfaces(registry)     // SoftwareUpgradeProposal
+	return registry
+}
+
+// NewCodec returns a protobuf codec using NewInterfaceRegistry
+func NewCodec() *codec.ProtoCodec {
+	return codec.NewProtoCodec(NewInterfaceRegistry()) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/codec#NewProtoCodec
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// ErrUnknownDenom is returned when an amount can not be converted because its denom has no metadata
+var ErrUnknownDenom = errors.New("unknown denom")
+
+// DenomsMetadata returns the bank metadata of the denoms: their base and display units with their exponents,
+// ie: uatom(exponent 0) and atom(exponent 6)
+// https://docs.cosmos.network/v0.46/modules/bank/02_state.html#denomination-metadata
+func (c *Client) DenomsMetadata(ctx context.Context, opts ...PageOption) ([]banktypes.Metadata, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]banktypes.Metadata, *query.PageResponse, error) {
+		res, err := banktypes.NewQueryClient(c.conn).DenomsMetadata(ctx, &banktypes.QueryDenomsMetadataRequest{Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Metadatas, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query denoms metadata: %w", err)
+	}
+	return results, nil
+}
+
+// LoadDenoms queries the metadata of all the denoms to convert their amounts
+func (c *Client) LoadDenoms(ctx context.Context) (*Denoms, error) {
+	metadata, err := c.DenomsMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewDenoms(metadata), nil
+}
+
+// Denoms converts amounts between the base and the display units of the denom metadata. A nil or empty Denoms
+// only knows the base denoms.
+type Denoms struct {
+	metadata []banktypes.Metadata
+}
+
+// NewDenoms returns Denoms for the metadata, ie: the result of DenomsMetadata
+func NewDenoms(metadata []banktypes.Metadata) *Denoms {
+	return &Denoms{metadata: metadata}
+}
+
+// Lookup returns the metadata with a unit named denom(the base, the display or any other unit or alias, case
+// insensitive) and the exponent of the unit
+func (d *Denoms) Lookup(denom string) (banktypes.Metadata, uint32, bool) {
+	if d == nil {
+		return banktypes.Metadata{}, 0, false
+	}
+	for _, metadata := range d.metadata {
+		for _, unit := range metadata.DenomUnits {
+			if strings.EqualFold(unit.Denom, denom) {
+				return metadata, unit.Exponent, true
+			}
+			for _, alias := range unit.Aliases {
+				if strings.EqualFold(alias, denom) {
+					return metadata, unit.Exponent, true
+				}
+			}
+		}
+	}
+	return banktypes.Metadata{}, 0, false
+}
+
+// ParseAmount parses an amount of the command line into a coin of the base denom:
+//   - an integer is an amount of defaultDenom, ie: 10000 is 10000uatom
+//   - a decimal with a unit of the metadata is converted with its exponent, ie: 0.01atom is 10000uatom
+//   - an integer with a denom without metadata is an amount of that base denom, ie: 5ibc/27394FB...
+func (d *Denoms) ParseAmount(amount string, defaultDenom string) (sdk.Coin, error) {
+	amount = strings.TrimSpace(amount)
+	if n, ok := sdk.NewIntFromString(amount); ok {
+		return sdk.NewCoin(defaultDenom, n), nil
+	}
+	coin, err := sdk.ParseDecCoin(amount) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#ParseDecCoin
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("amount %q: %w", amount, err)
+	}
+	metadata, exponent, ok := d.Lookup(coin.Denom)
+	if !ok {
+		// a denom without metadata is a base denom, before loading the metadata only defaultDenom is known
+		loaded := d != nil && len(d.metadata) > 0
+		if !coin.Amount.IsInteger() || !loaded && coin.Denom != defaultDenom {
+			return sdk.Coin{}, fmt.Errorf("amount %q: %w %s", amount, ErrUnknownDenom, coin.Denom)
+		}
+		return sdk.NewCoin(coin.Denom, coin.Amount.TruncateInt()), nil
+	}
+	base := coin.Amount.Mul(pow10(exponent))
+	if !base.IsInteger() {
+		return sdk.Coin{}, fmt.Errorf("amount %q has more than %d decimals", amount, exponent)
+	}
+	return sdk.NewCoin(metadata.Base, base.TruncateInt()), nil
+}
+
+// ToDisplay converts a coin of the base denom into the display unit of its metadata
+func (d *Denoms) ToDisplay(coin sdk.Coin) (sdk.DecCoin, bool) {
+	metadata, _, ok := d.Lookup(coin.Denom)
+	if !ok || metadata.Base != coin.Denom || metadata.Display == "" {
+		return sdk.DecCoin{}, false
+	}
+	_, exponent, ok := d.Lookup(metadata.Display)
+	if !ok {
+		return sdk.DecCoin{}, false
+	}
+	return sdk.DecCoin{Denom: metadata.Display, Amount: sdk.NewDecFromInt(coin.Amount).Quo(pow10(exponent))}, true
+}
+
+// Format renders the coins in their display units with the base amount, ie: 0.01atom(10000uatom), the coins
+// without metadata are rendered in the base denom
+func (d *Denoms) Format(coins ...sdk.Coin) string {
+	formatted := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		display, ok := d.ToDisplay(coin)
+		if !ok {
+			formatted = append(formatted, coin.String())
+			continue
+		}
+		formatted = append(formatted, fmt.Sprintf("%s%s(%s)", formatDec(display.Amount), display.Denom, coin.String()))
+	}
+	return strings.Join(formatted, ",")
+}
+
+func pow10(exponent uint32) sdk.Dec {
+	return sdk.NewDec(10).Power(uint64(exponent))
+}
+
+// the decimal without the trailing zeros, ie: 0.010000000000000000 is 0.01
+func formatDec(d sdk.Dec) string {
+	s := d.String()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
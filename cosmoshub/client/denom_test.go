@@ -0,0 +1,82 @@
+package client
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+var testAtom = banktypes.Metadata{
+	Base:    "uatom",
+	Display: "atom",
+	DenomUnits: []*banktypes.DenomUnit{
+		{Denom: "uatom", Exponent: 0, Aliases: []string{"microatom"}},
+		{Denom: "matom", Exponent: 3},
+		{Denom: "atom", Exponent: 6},
+	},
+}
+
+func TestParseAmount(t *testing.T) {
+	sut := NewDenoms([]banktypes.Metadata{testAtom})
+
+	for amount, expected := range map[string]sdk.Coin{
+		"10000":      sdk.NewInt64Coin("uatom", 10000),
+		"10000uatom": sdk.NewInt64Coin("uatom", 10000),
+		"0.01atom":   sdk.NewInt64Coin("uatom", 10000),
+		"0.01ATOM":   sdk.NewInt64Coin("uatom", 10000),
+		"2.5matom":   sdk.NewInt64Coin("uatom", 2500),
+		"5uosmo":     sdk.NewInt64Coin("uosmo", 5),
+	} {
+		coin, err := sut.ParseAmount(amount, "uatom")
+		if err != nil {
+			t.Errorf("ParseAmount %s error %v", amount, err)
+			continue
+		}
+		if !coin.IsEqual(expected) {
+			t.Errorf("ParseAmount %s should be %s but is %s", amount, expected, coin)
+		}
+	}
+	if _, err := sut.ParseAmount("0.0000001atom", "uatom"); err == nil {
+		t.Errorf("ParseAmount should fail with more decimals than the exponent")
+	}
+}
+
+func TestParseAmountWithoutMetadata(t *testing.T) {
+	var sut *Denoms
+
+	coin, err := sut.ParseAmount("10000uatom", "uatom")
+	if err != nil || !coin.IsEqual(sdk.NewInt64Coin("uatom", 10000)) {
+		t.Errorf("ParseAmount of the default denom should not need metadata but is %s %v", coin, err)
+	}
+	if _, err := sut.ParseAmount("0.01atom", "uatom"); err == nil {
+		t.Errorf("ParseAmount of a display denom without metadata should fail")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	sut := NewDenoms([]banktypes.Metadata{testAtom})
+
+	formatted := sut.Format(sdk.NewInt64Coin("uatom", 1500000), sdk.NewInt64Coin("uosmo", 7))
+
+	if formatted != "1.5atom(1500000uatom),7uosmo" {
+		t.Errorf("Format should render atom in the display unit but is %s", formatted)
+	}
+}
+
+func TestDenomTraceEncoding(t *testing.T) {
+	res := &denomTraceResponse{DenomTrace: DenomTrace{Path: "transfer/channel-141", BaseDenom: "uosmo"}}
+	b, err := res.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal error %v", err)
+	}
+
+	var sut denomTraceResponse
+	if err := sut.Unmarshal(b); err != nil {
+		t.Fatalf("Unmarshal error %v", err)
+	}
+
+	if sut.DenomTrace != res.DenomTrace || sut.DenomTrace.FullPath() != "transfer/channel-141/uosmo" {
+		t.Errorf("the decoded trace should be %s but is %s", res.DenomTrace.FullPath(), sut.DenomTrace.FullPath())
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/status"
+)
+
+// The errors of the queries and the transactions, test them with errors.Is: the gRPC status of the queries and the
+// ABCI code of the transactions are mapped to them
+var (
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSequenceMismatch  = errors.New("account sequence mismatch")
+	ErrTxFailed          = errors.New("transaction failed")
+)
+
+// The exit codes of the cosmoshub programs for the typed errors, scripts can tell the failures apart without parsing
+// the output. 2 is left to the invalid command line flags.
+const (
+	ExitError             = 1 // any other error
+	ExitAccountNotFound   = 3 // the account has not received any transfer yet
+	ExitInsufficientFunds = 4 // the balance can not pay the amount or the fee
+	ExitSequenceMismatch  = 5 // the account sequence changed, ie: another transaction of the same key
+	ExitTxFailed          = 6 // the transaction was rejected by CheckTx or its delivery failed
+)
+
+// ExitCode returns the exit code of an error, 0 when it is nil. The typed errors are checked before ErrTxFailed
+// because a transaction error can also be one of them.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrAccountNotFound):
+		return ExitAccountNotFound
+	case errors.Is(err, ErrInsufficientFunds):
+		return ExitInsufficientFunds
+	case errors.Is(err, ErrSequenceMismatch):
+		return ExitSequenceMismatch
+	case errors.Is(err, ErrTxFailed):
+		return ExitTxFailed
+	}
+	return ExitError
+}
+
+// TxError is a transaction rejected by CheckTx(Height 0) or whose delivery failed, the fee of a failed delivery is
+// paid. It is ErrTxFailed and, depending on its code, ErrInsufficientFunds or ErrSequenceMismatch.
+// The codes of the sdk codespace https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/types/errors/errors.go
+type TxError struct {
+	Hash      string
+	Height    int64
+	Codespace string
+	Code      uint32
+	Log       string
+}
+
+func (e *TxError) Error() string {
+	if e.Height == 0 {
+		return fmt.Sprintf("transaction %s rejected, codespace %s code %d: %s", e.Hash, e.Codespace, e.Code, e.Log)
+	}
+	return fmt.Sprintf("transaction %s failed at height %d, codespace %s code %d: %s", e.Hash, e.Height, e.Codespace, e.Code, e.Log)
+}
+
+func (e *TxError) Is(target error) bool {
+	switch target {
+	case ErrTxFailed:
+		return true
+	case ErrInsufficientFunds:
+		return e.Codespace == sdkerrors.RootCodespace && e.Code == sdkerrors.ErrInsufficientFunds.ABCICode()
+	case ErrSequenceMismatch:
+		return e.Codespace == sdkerrors.RootCodespace && e.Code == sdkerrors.ErrWrongSequence.ABCICode()
+	}
+	return false
+}
+
+// CheckTxResponse returns a *TxError when the response of Broadcast has a Code different than 0, nil otherwise
+func CheckTxResponse(res *sdk.TxResponse) error {
+	if res == nil || res.Code == 0 {
+		return nil
+	}
+	return &TxError{Hash: res.TxHash, Codespace: res.Codespace, Code: res.Code, Log: res.RawLog}
+}
+
+// Err returns a *TxError when the delivery of the transaction failed, nil otherwise
+func (r TxResult) Err() error {
+	if !r.Failed() {
+		return nil
+	}
+	return &TxError{Hash: r.Hash, Height: r.Height, Codespace: r.Codespace, Code: r.Code, Log: r.RawLog}
+}
+
+// An error of the gRPC status whose message has the description of an sdk error, the simulation returns the
+// errors of the ante handler this way, ie: "account sequence mismatch, expected 5, got 4: incorrect account sequence"
+type statusError struct {
+	kind error
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus keeps the gRPC code of the error, status.Code(err) still works
+func (e *statusError) GRPCStatus() *status.Status {
+	return status.Convert(e.err)
+}
+
+// the sdk errors mapped from the gRPC status messages
+var statusKinds = []struct {
+	sdkErr *sdkerrors.Error
+	kind   error
+}{
+	{sdkerrors.ErrInsufficientFunds, ErrInsufficientFunds},
+	{sdkerrors.ErrWrongSequence, ErrSequenceMismatch},
+}
+
+// Map the gRPC status of err to the typed errors, err is returned when it has no typed error
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, k := range statusKinds {
+		if strings.Contains(st.Message(), k.sdkErr.Error()) {
+			return &statusError{kind: k.kind, err: err}
+		}
+	}
+	return err
+}
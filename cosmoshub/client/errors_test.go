@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckTxResponse(t *testing.T) {
+	sut := CheckTxResponse(&sdk.TxResponse{TxHash: "AB", Codespace: "sdk", Code: 32, RawLog: "account sequence mismatch, expected 2, got 5: incorrect account sequence"})
+
+	var txErr *TxError
+	if !errors.As(sut, &txErr) || txErr.Hash != "AB" || txErr.Height != 0 {
+		t.Fatalf("CheckTxResponse should return the rejected transaction but returns %v", sut)
+	}
+	if !errors.Is(sut, ErrSequenceMismatch) || !errors.Is(sut, ErrTxFailed) || errors.Is(sut, ErrInsufficientFunds) {
+		t.Errorf("the code 32 of the sdk codespace should be ErrSequenceMismatch and ErrTxFailed but is %v", sut)
+	}
+	if err := CheckTxResponse(&sdk.TxResponse{TxHash: "AB"}); err != nil {
+		t.Errorf("an accepted transaction should not be an error but is %v", err)
+	}
+}
+
+func TestTxResultErr(t *testing.T) {
+	sut := TxResult{Hash: "AB", Height: 10, Codespace: "sdk", Code: 5, RawLog: "insufficient funds"}.Err()
+
+	if !errors.Is(sut, ErrInsufficientFunds) || sut.Error() != "transaction AB failed at height 10, codespace sdk code 5: insufficient funds" {
+		t.Errorf("the failed delivery should be ErrInsufficientFunds but is %v", sut)
+	}
+	if other := (TxResult{Codespace: "wasm", Code: 5}).Err(); errors.Is(other, ErrInsufficientFunds) {
+		t.Errorf("the code 5 of another codespace should not be ErrInsufficientFunds")
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	simulation := status.Error(codes.Unknown, "account sequence mismatch, expected 3, got 1: incorrect account sequence")
+	funds := status.Error(codes.Unknown, "1uatom is smaller than 100uatom: insufficient funds")
+
+	sut := fmt.Errorf("simulate tx: %w", fromStatus(simulation))
+
+	if !errors.Is(sut, ErrSequenceMismatch) || status.Code(errors.Unwrap(sut)) != codes.Unknown {
+		t.Errorf("the simulation error should be ErrSequenceMismatch keeping its status but is %v", sut)
+	}
+	if !errors.Is(fromStatus(funds), ErrInsufficientFunds) {
+		t.Errorf("the status should be ErrInsufficientFunds")
+	}
+	if other := errors.New("other"); fromStatus(other) != other {
+		t.Errorf("an error without status should not be mapped")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{errors.New("connection refused"), ExitError},
+		{fmt.Errorf("query account: %w", ErrAccountNotFound), ExitAccountNotFound},
+		{&TxError{Codespace: "sdk", Code: 5}, ExitInsufficientFunds},
+		{fromStatus(status.Error(codes.Unknown, "account sequence mismatch, expected 3, got 1: incorrect account sequence")), ExitSequenceMismatch},
+		{fmt.Errorf("chunk 1: %w", &TxError{Codespace: "sdk", Code: 32}), ExitSequenceMismatch},
+		{&TxError{Codespace: "sdk", Code: 4}, ExitTxFailed},
+	}
+	for _, test := range tests {
+		sut := ExitCode(test.err)
+
+		if sut != test.expected {
+			t.Errorf("ExitCode of %v should be %d but is %d", test.err, test.expected, sut)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tenderminttypes "github.com/tendermint/tendermint/types"
+)
+
+// TxEvent is a transaction notified by the tendermint websocket, its events are indexed by type.attribute, ie:
+// transfer.recipient or message.sender
+type TxEvent struct {
+	Hash   string
+	Height int64
+	Events map[string][]string
+}
+
+// DefaultMaxSubscriptions is the default max_subscriptions_per_client of the tendermint RPC, the node counts the
+// subscriptions of all the websockets of a client IP
+// https://docs.tendermint.com/v0.34/tendermint-core/configuration.html
+const DefaultMaxSubscriptions = 5
+
+// SubscribeTxs notifies the transactions matching any of the queries until ctx is done, ie: tm.event = 'Tx' AND
+// transfer.recipient = 'cosmos1...'. The queries share one websocket and each one is a subscription, a transaction
+// matching several queries is notified once per query. The channel is closed when ctx is done or all the
+// subscriptions ended(ie: the node unsubscribed them). It needs the RPC endpoint.
+// https://docs.tendermint.com/v0.34/tendermint-core/subscription.html
+func (c *Client) SubscribeTxs(ctx context.Context, queries ...string) (<-chan TxEvent, error) {
+	if c.rpcURL == "" {
+		return nil, errors.New("subscribe txs: the client has no RPC endpoint")
+	}
+	if len(queries) == 0 {
+		return nil, errors.New("subscribe txs: no query")
+	}
+	rpc, err := rpchttp.New(c.rpcURL, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("rpchttp.New %s: %w", c.rpcURL, err)
+	}
+	if err := rpc.Start(); err != nil {
+		return nil, fmt.Errorf("rpchttp.Start %s: %w", c.rpcURL, err)
+	}
+	subscriptions := make([]<-chan coretypes.ResultEvent, 0, len(queries))
+	for _, query := range queries {
+		subscribed, err := rpc.Subscribe(ctx, "subscribe-txs", query)
+		if err != nil {
+			rpc.Stop()
+			return nil, fmt.Errorf("rpchttp.Subscribe %s: %w", query, err)
+		}
+		subscriptions = append(subscriptions, subscribed)
+	}
+	events := make(chan TxEvent)
+	var wg sync.WaitGroup
+	for _, subscribed := range subscriptions {
+		wg.Add(1)
+		go func(subscribed <-chan coretypes.ResultEvent) {
+			defer wg.Done()
+			forwardTxEvents(ctx, subscribed, events)
+		}(subscribed)
+	}
+	go func() {
+		wg.Wait()
+		rpc.Stop()
+		close(events)
+	}()
+	return events, nil
+}
+
+// Send the transactions of a subscription to events until ctx is done or the subscription ends
+func forwardTxEvents(ctx context.Context, subscribed <-chan coretypes.ResultEvent, events chan<- TxEvent) {
+	for {
+		select {
+		case event, ok := <-subscribed:
+			if !ok {
+				return
+			}
+			data, ok := event.Data.(tenderminttypes.EventDataTx)
+			if !ok {
+				continue
+			}
+			txEvent := TxEvent{Height: data.Height, Events: event.Events}
+			if hashes := event.Events["tx.hash"]; len(hashes) > 0 {
+				txEvent.Hash = hashes[0]
+			}
+			select {
+			case events <- txEvent:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
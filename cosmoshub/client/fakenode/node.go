@@ -0,0 +1,219 @@
+// Package fakenode is an in-memory cosmos node served over a bufconn gRPC listener, it lets the programs be tested
+// without network access. It implements the auth, bank and authz queries and the tx service with a state scripted
+// by the test: accounts, balances, grants, denoms metadata and how the transactions are checked and delivered.
+// https://pkg.go.dev/google.golang.org/grpc/test/bufconn
+package fakenode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"cosmoshub/client"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// DefaultGasUsed is the gas used by each simulated or delivered transaction
+const DefaultGasUsed = 80000
+
+// buffer of the in-memory connections
+const bufSize = 1 << 20
+
+// Node is an in-memory cosmos node. The state is changed with its methods while it serves, they are safe for
+// concurrent use.
+type Node struct {
+	ChainID  string
+	Prefixes client.Bech32Prefixes // encode the addresses of the responses
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+	txConfig sdkclient.TxConfig
+
+	mu          sync.Mutex
+	accounts    map[string]*authtypes.BaseAccount // address bytes -> account
+	nextNumber  uint64
+	balances    map[string]sdk.Coins // address bytes -> balance
+	grants      []grant
+	metadatas   []banktypes.Metadata
+	height      int64
+	gasUsed     uint64
+	delay       int
+	rejectNext  []*sdk.TxResponse
+	failNext    *sdk.TxResponse
+	txs         map[string]*pendingTx // hash -> broadcast transaction
+	broadcasted []sdk.Tx
+}
+
+type grant struct {
+	granter       sdk.AccAddress
+	grantee       sdk.AccAddress
+	authorization authz.Authorization
+	expiration    *time.Time
+}
+
+// a transaction accepted by CheckTx, it is delivered after the GetTx queries of the inclusion delay
+type pendingTx struct {
+	tx       sdk.Tx
+	polls    int
+	response *sdk.TxResponse // set once it is included in a block
+	fail     *sdk.TxResponse
+}
+
+// Start serves a node of the chain over an in-memory listener, Close stops it
+func Start(chainID string) *Node {
+	n := &Node{
+		ChainID:  chainID,
+		Prefixes: client.CosmosPrefixes,
+		listener: bufconn.Listen(bufSize),
+		// the SDK messages are gogoproto messages, they are encoded with its codec like a real node does
+		server:   grpc.NewServer(grpc.ForceServerCodec(client.NewCodec().GRPCCodec())),
+		txConfig: client.NewTxConfig(),
+		accounts: map[string]*authtypes.BaseAccount{},
+		balances: map[string]sdk.Coins{},
+		txs:      map[string]*pendingTx{},
+		gasUsed:  DefaultGasUsed,
+		height:   1,
+	}
+	authtypes.RegisterQueryServer(n.server, &authServer{n: n})
+	banktypes.RegisterQueryServer(n.server, &bankServer{n: n})
+	authz.RegisterQueryServer(n.server, &authzServer{n: n})
+	typestx.RegisterServiceServer(n.server, &txServer{n: n})
+	go n.server.Serve(n.listener)
+	return n
+}
+
+// Dial returns a client connected to the node, the options configure it like client.Dial ones
+func (n *Node) Dial(opts ...client.Option) (*client.Client, error) {
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return n.listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(client.NewCodec().GRPCCodec())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("grpc.Dial bufnet: %w", err)
+	}
+	return client.New(conn, append([]client.Option{client.WithPrefixes(n.Prefixes)}, opts...)...), nil
+}
+
+// Close stops the server and closes the listener
+func (n *Node) Close() {
+	n.server.Stop()
+	n.listener.Close()
+}
+
+// AddAccount creates the account of address with the next account number and sequence 0, its balance is set to
+// coins. Returns the account number.
+func (n *Node) AddAccount(address sdk.AccAddress, coins ...sdk.Coin) uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	number := n.nextNumber
+	n.nextNumber++
+	n.accounts[string(address)] = authtypes.NewBaseAccount(address, nil, number, 0)
+	n.balances[string(address)] = sdk.NewCoins(coins...)
+	return number
+}
+
+// SetSequence sets the sequence of an account created with AddAccount
+func (n *Node) SetSequence(address sdk.AccAddress, sequence uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if acc, ok := n.accounts[string(address)]; ok {
+		acc.Sequence = sequence
+	}
+}
+
+// Sequence returns the sequence of an account, it is incremented by each transaction accepted by CheckTx
+func (n *Node) Sequence(address sdk.AccAddress) uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if acc, ok := n.accounts[string(address)]; ok {
+		return acc.Sequence
+	}
+	return 0
+}
+
+// SetBalance replaces the balance of an address, it does not need an account
+func (n *Node) SetBalance(address sdk.AccAddress, coins ...sdk.Coin) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.balances[string(address)] = sdk.NewCoins(coins...)
+}
+
+// Balance returns the balance of an address
+func (n *Node) Balance(address sdk.AccAddress) sdk.Coins {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.balances[string(address)]
+}
+
+// AddGrant stores an authorization granted by granter to grantee, without expiration when it is nil
+func (n *Node) AddGrant(granter sdk.AccAddress, grantee sdk.AccAddress, authorization authz.Authorization, expiration *time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.grants = append(n.grants, grant{granter: granter, grantee: grantee, authorization: authorization, expiration: expiration})
+}
+
+// SetDenomsMetadata replaces the bank metadata of the denoms
+func (n *Node) SetDenomsMetadata(metadatas ...banktypes.Metadata) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.metadatas = metadatas
+}
+
+// SetGasUsed sets the gas used by the simulations and the delivered transactions, DefaultGasUsed by default
+func (n *Node) SetGasUsed(gasUsed uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.gasUsed = gasUsed
+}
+
+// SetInclusionDelay sets the GetTx queries answered with NotFound before a broadcast transaction is included in a
+// block, 0 by default so it is included on the first query
+func (n *Node) SetInclusionDelay(polls int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.delay = polls
+}
+
+// RejectNextTx makes CheckTx reject the next broadcast transaction with the code, codespace and log, ie: code 5
+// insufficient funds of the sdk codespace. The transaction is not included and the sequence is not incremented.
+// Each call rejects one more transaction, the rejections apply to the following broadcasts in order.
+func (n *Node) RejectNextTx(code uint32, codespace string, log string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.rejectNext = append(n.rejectNext, &sdk.TxResponse{Code: code, Codespace: codespace, RawLog: log})
+}
+
+// FailNextTx makes the delivery of the next broadcast transaction fail with the code, codespace and log. It is
+// included in a block, the fee is paid and the sequence incremented but its messages are not applied.
+func (n *Node) FailNextTx(code uint32, codespace string, log string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.failNext = &sdk.TxResponse{Code: code, Codespace: codespace, RawLog: log}
+}
+
+// Txs returns the decoded transactions accepted by CheckTx in broadcast order
+func (n *Node) Txs() []sdk.Tx {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]sdk.Tx(nil), n.broadcasted...)
+}
+
+// Height returns the height of the last block, each included transaction is in its own block
+func (n *Node) Height() int64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.height
+}
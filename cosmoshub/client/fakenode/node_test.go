@@ -0,0 +1,112 @@
+package fakenode
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	testAddress = sdk.AccAddress([]byte("address_____________"))
+	testGrantee = sdk.AccAddress([]byte("grantee_____________"))
+)
+
+func startNode(t *testing.T) (*Node, *client.Client) {
+	node := Start("test-chain")
+	t.Cleanup(node.Close)
+	c, err := node.Dial(client.WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Dial error %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return node, c
+}
+
+func TestGetAccount(t *testing.T) {
+	node, c := startNode(t)
+	node.AddAccount(sdk.AccAddress([]byte("other_______________")))
+	number := node.AddAccount(testAddress)
+	node.SetSequence(testAddress, 7)
+
+	sut, err := c.GetAccount(context.Background(), testAddress)
+
+	if err != nil {
+		t.Fatalf("GetAccount error %v", err)
+	}
+	if sut.GetAccountNumber() != number || number != 1 || sut.GetSequence() != 7 || !sut.GetAddress().Equals(testAddress) {
+		t.Errorf("GetAccount should return the account number 1 and sequence 7 but returns %v", sut)
+	}
+}
+
+func TestGetAccountNotFound(t *testing.T) {
+	_, c := startNode(t)
+
+	_, err := c.GetAccount(context.Background(), testAddress)
+
+	if status.Code(errors.Unwrap(err)) != codes.NotFound || !errors.Is(err, client.ErrAccountNotFound) {
+		t.Errorf("GetAccount of an unknown address should be NotFound and ErrAccountNotFound but is %v", err)
+	}
+}
+
+func TestGetAllBalancesPages(t *testing.T) {
+	node, c := startNode(t)
+	var coins []sdk.Coin
+	for _, denom := range []string{"uatom", "ubtc", "ueth", "uosmo", "ustake"} {
+		coins = append(coins, sdk.NewInt64Coin(denom, 10))
+	}
+	node.SetBalance(testAddress, coins...)
+
+	sut, err := c.GetAllBalances(context.Background(), testAddress, client.PageOffset(1), client.PageLimit(3))
+
+	if err != nil {
+		t.Fatalf("GetAllBalances error %v", err)
+	}
+	if sut.String() != "10ubtc,10ueth,10uosmo" {
+		t.Errorf("GetAllBalances should return the 3 balances after the first but returns %s", sut)
+	}
+}
+
+func TestGrants(t *testing.T) {
+	node, c := startNode(t)
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	node.AddGrant(testAddress, testGrantee, banktypes.NewSendAuthorization(sdk.NewCoins(sdk.NewInt64Coin("uatom", 100))), &expiration)
+	node.AddGrant(testAddress, testGrantee, authz.NewGenericAuthorization("/cosmos.gov.v1beta1.MsgVote"), nil)
+
+	sut, err := c.Grants(context.Background(), testAddress, testGrantee, "/cosmos.gov.v1beta1.MsgVote")
+
+	if err != nil {
+		t.Fatalf("Grants error %v", err)
+	}
+	if len(sut) != 1 || sut[0].Expiration != nil {
+		t.Fatalf("Grants should return the vote grant but returns %v", sut)
+	}
+	authorization, err := c.UnpackAuthorization(sut[0].Authorization)
+	if err != nil || authorization.MsgTypeURL() != "/cosmos.gov.v1beta1.MsgVote" {
+		t.Errorf("the grant should unpack into the vote authorization but is %v %v", authorization, err)
+	}
+	if _, err := c.Grants(context.Background(), testAddress, testGrantee, "/cosmos.staking.v1beta1.MsgDelegate"); status.Code(errors.Unwrap(err)) != codes.NotFound {
+		t.Errorf("Grants of a message type without grant should be NotFound but is %v", err)
+	}
+}
+
+func TestWaitForTxUnknown(t *testing.T) {
+	_, c := startNode(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.WaitForTx(ctx, "ABCD")
+
+	// the deadline expires while waiting for the next poll or during a GetTx query
+	if !errors.Is(err, context.DeadlineExceeded) && status.Code(errors.Unwrap(err)) != codes.DeadlineExceeded {
+		t.Errorf("WaitForTx of a transaction never broadcast should time out but is %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package fakenode
+
+import (
+	"context"
+	"strconv"
+
+	"cosmoshub/client"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// page size when the request does not set a limit, the one of the SDK
+const defaultLimit = 100
+
+// x/auth queries, only Account is implemented
+type authServer struct {
+	authtypes.UnimplementedQueryServer
+	n *Node
+}
+
+func (s *authServer) Account(ctx context.Context, req *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	address, err := parseAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	acc, ok := s.n.accounts[string(address)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Address)
+	}
+	any, err := codectypes.NewAnyWithValue(acc)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &authtypes.QueryAccountResponse{Account: any}, nil
+}
+
+// x/bank queries of the balances and the denoms metadata
+type bankServer struct {
+	banktypes.UnimplementedQueryServer
+	n *Node
+}
+
+func (s *bankServer) Balance(ctx context.Context, req *banktypes.QueryBalanceRequest) (*banktypes.QueryBalanceResponse, error) {
+	address, err := parseAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if err := sdk.ValidateDenom(req.Denom); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	balance := sdk.NewCoin(req.Denom, s.n.balances[string(address)].AmountOf(req.Denom))
+	return &banktypes.QueryBalanceResponse{Balance: &balance}, nil
+}
+
+func (s *bankServer) AllBalances(ctx context.Context, req *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	address, err := parseAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	balances, res := paginate(s.n.balances[string(address)], req.Pagination)
+	return &banktypes.QueryAllBalancesResponse{Balances: balances, Pagination: res}, nil
+}
+
+func (s *bankServer) DenomsMetadata(ctx context.Context, req *banktypes.QueryDenomsMetadataRequest) (*banktypes.QueryDenomsMetadataResponse, error) {
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	metadatas, res := paginate(s.n.metadatas, req.Pagination)
+	return &banktypes.QueryDenomsMetadataResponse{Metadatas: metadatas, Pagination: res}, nil
+}
+
+// x/authz queries of the grants
+type authzServer struct {
+	authz.UnimplementedQueryServer
+	n *Node
+}
+
+func (s *authzServer) Grants(ctx context.Context, req *authz.QueryGrantsRequest) (*authz.QueryGrantsResponse, error) {
+	granter, err := parseAddress(req.Granter)
+	if err != nil {
+		return nil, err
+	}
+	grantee, err := parseAddress(req.Grantee)
+	if err != nil {
+		return nil, err
+	}
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	var grants []*authz.Grant
+	for _, g := range s.n.grants {
+		if !g.granter.Equals(granter) || !g.grantee.Equals(grantee) {
+			continue
+		}
+		if req.MsgTypeUrl != "" && g.authorization.MsgTypeURL() != req.MsgTypeUrl {
+			continue
+		}
+		any, err := codectypes.NewAnyWithValue(g.authorization)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		grants = append(grants, &authz.Grant{Authorization: any, Expiration: g.expiration})
+	}
+	// like the SDK, the query of a message type fails when there is no grant for it
+	if req.MsgTypeUrl != "" && len(grants) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no authorization found for %s type", req.MsgTypeUrl)
+	}
+	grants, res := paginate(grants, req.Pagination)
+	return &authz.QueryGrantsResponse{Grants: grants, Pagination: res}, nil
+}
+
+func (s *authzServer) GranterGrants(ctx context.Context, req *authz.QueryGranterGrantsRequest) (*authz.QueryGranterGrantsResponse, error) {
+	granter, err := parseAddress(req.Granter)
+	if err != nil {
+		return nil, err
+	}
+	grants, res, err := s.grantAuthorizations(func(g grant) bool { return g.granter.Equals(granter) }, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	return &authz.QueryGranterGrantsResponse{Grants: grants, Pagination: res}, nil
+}
+
+func (s *authzServer) GranteeGrants(ctx context.Context, req *authz.QueryGranteeGrantsRequest) (*authz.QueryGranteeGrantsResponse, error) {
+	grantee, err := parseAddress(req.Grantee)
+	if err != nil {
+		return nil, err
+	}
+	grants, res, err := s.grantAuthorizations(func(g grant) bool { return g.grantee.Equals(grantee) }, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	return &authz.QueryGranteeGrantsResponse{Grants: grants, Pagination: res}, nil
+}
+
+// the page of the grants matching the filter with their granter and grantee
+func (s *authzServer) grantAuthorizations(match func(g grant) bool, page *query.PageRequest) ([]*authz.GrantAuthorization, *query.PageResponse, error) {
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	var grants []*authz.GrantAuthorization
+	for _, g := range s.n.grants {
+		if !match(g) {
+			continue
+		}
+		any, err := codectypes.NewAnyWithValue(g.authorization)
+		if err != nil {
+			return nil, nil, status.Error(codes.Internal, err.Error())
+		}
+		grants = append(grants, &authz.GrantAuthorization{
+			Granter:       s.n.Prefixes.AccAddress(g.granter),
+			Grantee:       s.n.Prefixes.AccAddress(g.grantee),
+			Authorization: any,
+			Expiration:    g.expiration,
+		})
+	}
+	grants, res := paginate(grants, page)
+	return grants, res, nil
+}
+
+// The bech32 address of a request, any prefix is accepted
+func parseAddress(address string) (sdk.AccAddress, error) {
+	if address == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty address string is not allowed")
+	}
+	_, bz, err := client.DecodeAddress(address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s: %s", address, err)
+	}
+	return bz, nil
+}
+
+// The page of items requested, the next key is the index of the first item of the next page. The key takes
+// precedence over the offset like in the SDK.
+func paginate[T any](items []T, page *query.PageRequest) ([]T, *query.PageResponse) {
+	if page == nil {
+		page = &query.PageRequest{}
+	}
+	if page.Reverse {
+		reversed := make([]T, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+	start := int(page.Offset)
+	if len(page.Key) > 0 {
+		start, _ = strconv.Atoi(string(page.Key))
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+	limit := int(page.Limit)
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	res := &query.PageResponse{}
+	if page.CountTotal {
+		res.Total = uint64(len(items))
+	}
+	if end < len(items) {
+		res.NextKey = []byte(strconv.Itoa(end))
+	}
+	return items[start:end], res
+}
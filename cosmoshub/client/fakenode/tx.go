@@ -0,0 +1,242 @@
+package fakenode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tx service: simulation, broadcast and query of the included transactions
+type txServer struct {
+	typestx.UnimplementedServiceServer
+	n *Node
+}
+
+func (s *txServer) Simulate(ctx context.Context, req *typestx.SimulateRequest) (*typestx.SimulateResponse, error) {
+	tx, err := s.n.txConfig.TxDecoder()(req.TxBytes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode tx: %s", err)
+	}
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	gasWanted := uint64(0)
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		gasWanted = feeTx.GetGas()
+	}
+	return &typestx.SimulateResponse{GasInfo: &sdk.GasInfo{GasWanted: gasWanted, GasUsed: s.n.gasUsed}, Result: &sdk.Result{}}, nil
+}
+
+// BroadcastTx runs the checks of the ante handler like CheckTx: signers sequence and signature, and fee balance.
+// An accepted transaction pays the fee, increments the sequences and waits to be delivered.
+func (s *txServer) BroadcastTx(ctx context.Context, req *typestx.BroadcastTxRequest) (*typestx.BroadcastTxResponse, error) {
+	hash := fmt.Sprintf("%X", tmhash.Sum(req.TxBytes))
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	res := s.n.checkTx(req.TxBytes)
+	res.TxHash = hash
+	return &typestx.BroadcastTxResponse{TxResponse: res}, nil
+}
+
+func (s *txServer) GetTx(ctx context.Context, req *typestx.GetTxRequest) (*typestx.GetTxResponse, error) {
+	s.n.mu.Lock()
+	defer s.n.mu.Unlock()
+	pending, ok := s.n.txs[strings.ToUpper(req.Hash)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+	}
+	if pending.response == nil {
+		pending.polls++
+		if pending.polls <= s.n.delay {
+			return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+		}
+		pending.response = s.n.deliverTx(strings.ToUpper(req.Hash), pending)
+	}
+	return &typestx.GetTxResponse{TxResponse: pending.response}, nil
+}
+
+// The CheckTx result of the transaction, the mutex must be locked
+func (n *Node) checkTx(txBytes []byte) *sdk.TxResponse {
+	if len(n.rejectNext) > 0 {
+		res := n.rejectNext[0]
+		n.rejectNext = n.rejectNext[1:]
+		return res
+	}
+	tx, err := n.txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return abciError(sdkerrors.ErrTxDecode, err.Error())
+	}
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return abciError(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return abciError(sdkerrors.ErrTxDecode, err.Error())
+	}
+	signers := sigTx.GetSigners()
+	if len(sigs) != len(signers) {
+		return abciError(sdkerrors.ErrUnauthorized, fmt.Sprintf("wrong number of signers; expected %d, got %d", len(signers), len(sigs)))
+	}
+	for i, sig := range sigs {
+		acc, ok := n.accounts[string(signers[i])]
+		if !ok {
+			return abciError(sdkerrors.ErrUnknownAddress, fmt.Sprintf("account %s does not exist", n.Prefixes.AccAddress(signers[i])))
+		}
+		if !bytes.Equal(sig.PubKey.Address(), signers[i]) {
+			return abciError(sdkerrors.ErrInvalidPubKey, fmt.Sprintf("pubKey does not match signer address %s with signer index: %d", n.Prefixes.AccAddress(signers[i]), i))
+		}
+		if sig.Sequence != acc.Sequence {
+			return abciError(sdkerrors.ErrWrongSequence, fmt.Sprintf("account sequence mismatch, expected %d, got %d", acc.Sequence, sig.Sequence))
+		}
+		signerData := authsigning.SignerData{
+			Address:       n.Prefixes.AccAddress(signers[i]),
+			ChainID:       n.ChainID,
+			AccountNumber: acc.AccountNumber,
+			Sequence:      acc.Sequence,
+		}
+		if err := authsigning.VerifySignature(sig.PubKey, signerData, sig.Data, n.txConfig.SignModeHandler(), tx); err != nil {
+			return abciError(sdkerrors.ErrUnauthorized, fmt.Sprintf("signature verification failed; please verify account number (%d) and chain-id (%s)", acc.AccountNumber, n.ChainID))
+		}
+	}
+
+	// the fee granter pays the fee when it is set, the allowance is not checked
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return abciError(sdkerrors.ErrTxDecode, "transaction without fee")
+	}
+	payer := feeTx.FeePayer()
+	if granter := feeTx.FeeGranter(); granter != nil {
+		payer = granter
+	}
+	fee := feeTx.GetFee()
+	balance := n.balances[string(payer)]
+	if !balance.IsAllGTE(fee) {
+		return abciError(sdkerrors.ErrInsufficientFunds, fmt.Sprintf("%s is smaller than %s", balance, fee))
+	}
+	n.balances[string(payer)] = balance.Sub(fee...)
+	for i, sig := range sigs {
+		acc := n.accounts[string(signers[i])]
+		if acc.GetPubKey() == nil {
+			acc.SetPubKey(sig.PubKey)
+		}
+		acc.Sequence++
+	}
+	n.txs[fmt.Sprintf("%X", tmhash.Sum(txBytes))] = &pendingTx{tx: tx, fail: n.failNext}
+	n.failNext = nil
+	n.broadcasted = append(n.broadcasted, tx)
+	return &sdk.TxResponse{RawLog: "[]"}
+}
+
+// Include the transaction in a new block and apply its messages, the mutex must be locked
+func (n *Node) deliverTx(hash string, pending *pendingTx) *sdk.TxResponse {
+	n.height++
+	res := &sdk.TxResponse{TxHash: hash, Height: n.height, GasUsed: int64(n.gasUsed), Timestamp: time.Now().UTC().Format(time.RFC3339)}
+	if feeTx, ok := pending.tx.(sdk.FeeTx); ok {
+		res.GasWanted = int64(feeTx.GetGas())
+	}
+	if pending.fail != nil {
+		res.Code, res.Codespace, res.RawLog = pending.fail.Code, pending.fail.Codespace, pending.fail.RawLog
+		return res
+	}
+	// the messages are applied to a copy of the balances, so a failed message does not apply the previous ones
+	balances := make(map[string]sdk.Coins, len(n.balances))
+	for address, coins := range n.balances {
+		balances[address] = coins
+	}
+	for i, msg := range pending.tx.GetMsgs() {
+		events, err := n.applyMsg(balances, msg)
+		if err != nil {
+			failed := abciError(err, "")
+			res.Code, res.Codespace, res.RawLog = failed.Code, failed.Codespace, fmt.Sprintf("failed to execute message; message index: %d: %s", i, err)
+			return res
+		}
+		events = append(sdk.StringEvents{{Type: sdk.EventTypeMessage, Attributes: []sdk.Attribute{{Key: sdk.AttributeKeyAction, Value: sdk.MsgTypeURL(msg)}}}}, events...)
+		res.Logs = append(res.Logs, sdk.ABCIMessageLog{MsgIndex: uint32(i), Events: events})
+	}
+	n.balances = balances
+	res.RawLog = res.Logs.String()
+	return res
+}
+
+// Apply the bank transfers, the other messages are accepted without changing the state
+func (n *Node) applyMsg(balances map[string]sdk.Coins, msg sdk.Msg) (sdk.StringEvents, error) {
+	switch m := msg.(type) {
+	case *banktypes.MsgSend:
+		from, err := parseAddress(m.FromAddress)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseAddress(m.ToAddress)
+		if err != nil {
+			return nil, err
+		}
+		if err := transfer(balances, from, to, m.Amount); err != nil {
+			return nil, err
+		}
+		return sdk.StringEvents{transferEvent(m.FromAddress, m.ToAddress, m.Amount)}, nil
+	case *banktypes.MsgMultiSend:
+		var events sdk.StringEvents
+		for _, input := range m.Inputs {
+			from, err := parseAddress(input.Address)
+			if err != nil {
+				return nil, err
+			}
+			if !balances[string(from)].IsAllGTE(input.Coins) {
+				return nil, sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", balances[string(from)], input.Coins)
+			}
+			balances[string(from)] = balances[string(from)].Sub(input.Coins...)
+		}
+		for _, output := range m.Outputs {
+			to, err := parseAddress(output.Address)
+			if err != nil {
+				return nil, err
+			}
+			balances[string(to)] = balances[string(to)].Add(output.Coins...)
+			events = append(events, sdk.StringEvent{Type: banktypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+				{Key: banktypes.AttributeKeyRecipient, Value: output.Address},
+				{Key: sdk.AttributeKeyAmount, Value: output.Coins.String()},
+			}})
+		}
+		return events, nil
+	default:
+		return nil, nil
+	}
+}
+
+func transfer(balances map[string]sdk.Coins, from sdk.AccAddress, to sdk.AccAddress, amount sdk.Coins) error {
+	if !balances[string(from)].IsAllGTE(amount) {
+		return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", balances[string(from)], amount)
+	}
+	balances[string(from)] = balances[string(from)].Sub(amount...)
+	balances[string(to)] = balances[string(to)].Add(amount...)
+	return nil
+}
+
+func transferEvent(sender string, recipient string, amount sdk.Coins) sdk.StringEvent {
+	return sdk.StringEvent{Type: banktypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+		{Key: banktypes.AttributeKeyRecipient, Value: recipient},
+		{Key: banktypes.AttributeKeySender, Value: sender},
+		{Key: sdk.AttributeKeyAmount, Value: amount.String()},
+	}}
+}
+
+// The response of a failed check or delivery with the code and codespace of the SDK error, the log is the one of
+// the SDK: the message followed by the error description
+func abciError(err error, log string) *sdk.TxResponse {
+	codespace, code, description := sdkerrors.ABCIInfo(err, false)
+	if log != "" {
+		description = log + ": " + description
+	}
+	return &sdk.TxResponse{Code: code, Codespace: codespace, RawLog: description}
+}
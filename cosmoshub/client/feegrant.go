@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+// Allowance returns the fee allowance given by granter to grantee
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/feegrant#QueryClient
+func (c *Client) Allowance(ctx context.Context, granter sdk.AccAddress, grantee sdk.AccAddress) (*feegrant.Grant, error) {
+	res, err := feegrant.NewQueryClient(c.conn).Allowance(ctx, &feegrant.QueryAllowanceRequest{
+		Granter: c.prefixes.AccAddress(granter),
+		Grantee: c.prefixes.AccAddress(grantee),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query allowance %s to %s: %w", c.prefixes.AccAddress(granter), c.prefixes.AccAddress(grantee), err)
+	}
+	return res.Allowance, nil
+}
+
+// GranteeAllowances returns the fee allowances received by grantee
+func (c *Client) GranteeAllowances(ctx context.Context, grantee sdk.AccAddress, opts ...PageOption) ([]*feegrant.Grant, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]*feegrant.Grant, *query.PageResponse, error) {
+		res, err := feegrant.NewQueryClient(c.conn).Allowances(ctx, &feegrant.QueryAllowancesRequest{Grantee: c.prefixes.AccAddress(grantee), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Allowances, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query grantee allowances %s: %w", c.prefixes.AccAddress(grantee), err)
+	}
+	return results, nil
+}
+
+// GranterAllowances returns the fee allowances given by granter
+func (c *Client) GranterAllowances(ctx context.Context, granter sdk.AccAddress, opts ...PageOption) ([]*feegrant.Grant, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]*feegrant.Grant, *query.PageResponse, error) {
+		res, err := feegrant.NewQueryClient(c.conn).AllowancesByGranter(ctx, &feegrant.QueryAllowancesByGranterRequest{Granter: c.prefixes.AccAddress(granter), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Allowances, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query granter allowances %s: %w", c.prefixes.AccAddress(granter), err)
+	}
+	return results, nil
+}
+
+// UnpackAllowance unpacks the Any of a fee grant into the concrete allowance: BasicAllowance, PeriodicAllowance or
+// AllowedMsgAllowance
+func (c *Client) UnpackAllowance(any *codectypes.Any) (feegrant.FeeAllowanceI, error) {
+	var allowance feegrant.FeeAllowanceI
+	if err := c.cdc.UnpackAny(any, &allowance); err != nil {
+		return nil, fmt.Errorf("unpack allowance %s: %w", any.GetTypeUrl(), err)
+	}
+	return allowance, nil
+}
+
+// DescribeAllowance renders the spend limits and expiration of the built-in allowances
+func DescribeAllowance(allowance feegrant.FeeAllowanceI) string {
+	switch a := allowance.(type) {
+	case *feegrant.BasicAllowance:
+		return describeBasicAllowance(*a)
+	case *feegrant.PeriodicAllowance:
+		return fmt.Sprintf("periodic %s, period %s limit %s can spend %s until %s", describeBasicAllowance(a.Basic), a.Period,
+			describeCoins(a.PeriodSpendLimit), describeCoins(a.PeriodCanSpend), a.PeriodReset.Format("2006-01-02 15:04:05 MST"))
+	case *feegrant.AllowedMsgAllowance:
+		inner, err := a.GetAllowance()
+		if err != nil {
+			return fmt.Sprintf("allowed messages %s", strings.Join(a.AllowedMessages, ","))
+		}
+		return fmt.Sprintf("%s, allowed messages %s", DescribeAllowance(inner), strings.Join(a.AllowedMessages, ","))
+	default:
+		return fmt.Sprintf("%T", allowance)
+	}
+}
+
+func describeBasicAllowance(a feegrant.BasicAllowance) string {
+	expiration := "never"
+	if a.Expiration != nil {
+		expiration = a.Expiration.Format("2006-01-02 15:04:05 MST")
+	}
+	return fmt.Sprintf("spend limit %s expiration %s", describeCoins(a.SpendLimit), expiration)
+}
+
+// empty spend limits are unlimited
+func describeCoins(coins sdk.Coins) string {
+	if coins.Empty() {
+		return "unlimited"
+	}
+	return coins.String()
+}
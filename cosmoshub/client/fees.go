@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// DefaultGasAdjustment multiplies the simulated gas, the gas used can change between the simulation and the execution
+const DefaultGasAdjustment = 1.3
+
+// Simulate executes the transaction without committing it and returns the gas used. The signatures can be empty
+// but the signer infos(public key and sequence) must be set.
+// https://docs.cosmos.network/v0.46/run-node/txs.html#simulating-a-transaction
+func (c *Client) Simulate(ctx context.Context, txBytes []byte) (*sdk.GasInfo, error) {
+	res, err := typestx.NewServiceClient(c.conn).Simulate(ctx, &typestx.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		return nil, fmt.Errorf("simulate tx: %w", fromStatus(err))
+	}
+	return res.GasInfo, nil
+}
+
+// AdjustGas multiplies the gas used by the adjustment: gasLimit = ceil(gasUsed * adjustment)
+func AdjustGas(gasUsed uint64, adjustment float64) uint64 {
+	if adjustment <= 0 {
+		adjustment = DefaultGasAdjustment
+	}
+	return uint64(math.Ceil(float64(gasUsed) * adjustment))
+}
+
+// ComputeFee returns the fee to pay for a gas limit: fee = ceil(gasLimit * gasPrice)
+func ComputeFee(gasLimit uint64, gasPrice sdk.DecCoin) sdk.Coin {
+	amount := gasPrice.Amount.MulInt64(int64(gasLimit)).Ceil().TruncateInt()
+	return sdk.NewCoin(gasPrice.Denom, amount)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+)
+
+// Proposals returns the governance proposals with status, all of them when it is StatusNil. A non empty voter or
+// depositor returns only the proposals they voted or deposited on.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1#QueryClient
+func (c *Client) Proposals(ctx context.Context, status govv1beta1.ProposalStatus, voter sdk.AccAddress, depositor sdk.AccAddress, opts ...PageOption) ([]govv1beta1.Proposal, error) {
+	req := &govv1beta1.QueryProposalsRequest{ProposalStatus: status}
+	if !voter.Empty() {
+		req.Voter = c.prefixes.AccAddress(voter)
+	}
+	if !depositor.Empty() {
+		req.Depositor = c.prefixes.AccAddress(depositor)
+	}
+	results, err := paginate(opts, func(page *query.PageRequest) ([]govv1beta1.Proposal, *query.PageResponse, error) {
+		req.Pagination = page
+		res, err := govv1beta1.NewQueryClient(c.conn).Proposals(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Proposals, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query proposals %s: %w", status, err)
+	}
+	return results, nil
+}
+
+// Proposal returns the governance proposal with id
+func (c *Client) Proposal(ctx context.Context, id uint64) (*govv1beta1.Proposal, error) {
+	res, err := govv1beta1.NewQueryClient(c.conn).Proposal(ctx, &govv1beta1.QueryProposalRequest{ProposalId: id})
+	if err != nil {
+		return nil, fmt.Errorf("query proposal %d: %w", id, err)
+	}
+	return &res.Proposal, nil
+}
+
+// TallyResult returns the current tally of a proposal in its voting period or the final one when it ended
+func (c *Client) TallyResult(ctx context.Context, id uint64) (*govv1beta1.TallyResult, error) {
+	res, err := govv1beta1.NewQueryClient(c.conn).TallyResult(ctx, &govv1beta1.QueryTallyResultRequest{ProposalId: id})
+	if err != nil {
+		return nil, fmt.Errorf("query tally result %d: %w", id, err)
+	}
+	return &res.Tally, nil
+}
+
+// Vote returns the vote of voter on a proposal, it is not found when voter has not voted
+func (c *Client) Vote(ctx context.Context, id uint64, voter sdk.AccAddress) (*govv1beta1.Vote, error) {
+	res, err := govv1beta1.NewQueryClient(c.conn).Vote(ctx, &govv1beta1.QueryVoteRequest{ProposalId: id, Voter: c.prefixes.AccAddress(voter)})
+	if err != nil {
+		return nil, fmt.Errorf("query vote %d of %s: %w", id, c.prefixes.AccAddress(voter), err)
+	}
+	return &res.Vote, nil
+}
+
+// ProposalTitle unpacks the content of a proposal and returns its title, the content type URL when the content type
+// is not registered in NewInterfaceRegistry
+func (c *Client) ProposalTitle(proposal govv1beta1.Proposal) string {
+	if err := proposal.UnpackInterfaces(c.cdc); err != nil {
+		return proposal.Content.GetTypeUrl()
+	}
+	return proposal.GetTitle()
+}
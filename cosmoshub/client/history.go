@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// TxPage is a page of the transactions matching an event query, Txs[i] is the decoded transaction of Responses[i]
+type TxPage struct {
+	Responses []*sdk.TxResponse
+	Txs       []*typestx.Tx
+	Total     uint64 // transactions matching the query in all the pages
+}
+
+// SearchTxs returns the page(starting at 1) of limit transactions matching all the events, ie:
+// message.sender='cosmos1...' or transfer.recipient='cosmos1...'. The node must index the transactions.
+// https://docs.cosmos.network/v0.46/core/events.html#subscribing-to-events
+func (c *Client) SearchTxs(ctx context.Context, events []string, page uint64, limit uint64, orderBy typestx.OrderBy) (*TxPage, error) {
+	res, err := typestx.NewServiceClient(c.conn).GetTxsEvent(ctx, &typestx.GetTxsEventRequest{
+		Events:  events,
+		OrderBy: orderBy,
+		Page:    page,
+		Limit:   limit,
+		// nodes before v0.46 only read the deprecated pagination
+		Pagination: &query.PageRequest{Offset: (page - 1) * limit, Limit: limit, CountTotal: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search txs %s page %d: %w", strings.Join(events, " AND "), page, err)
+	}
+	total := res.Total
+	if total == 0 && res.Pagination != nil {
+		total = res.Pagination.Total
+	}
+	return &TxPage{Responses: res.TxResponses, Txs: res.Txs, Total: total}, nil
+}
+
+// FeePayer returns the account that paid the fee of tx: the fee granter, the fee payer or the first signer
+// https://docs.cosmos.network/v0.46/core/transactions.html#transaction-generation
+func (c *Client) FeePayer(tx *typestx.Tx) (sdk.AccAddress, error) {
+	fee := tx.GetAuthInfo().GetFee()
+	if fee.GetGranter() != "" {
+		return c.prefixes.ParseAccAddress(fee.Granter)
+	}
+	if fee.GetPayer() != "" {
+		return c.prefixes.ParseAccAddress(fee.Payer)
+	}
+	signers := tx.GetAuthInfo().GetSignerInfos()
+	if len(signers) == 0 || signers[0].PublicKey == nil {
+		return nil, fmt.Errorf("tx without signer public key")
+	}
+	// the signer infos are in the order of the signers, the public key avoids unpacking the messages
+	var pubKey cryptotypes.PubKey
+	if err := c.cdc.UnpackAny(signers[0].PublicKey, &pubKey); err != nil {
+		return nil, fmt.Errorf("unpack signer public key %s: %w", signers[0].PublicKey.GetTypeUrl(), err)
+	}
+	return sdk.AccAddress(pubKey.Address()), nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// DenomTrace is the origin of an IBC voucher denom: the transfer channels it went through and its denom on the
+// source chain, ie: transfer/channel-141 and uosmo
+// https://github.com/cosmos/ibc-go/blob/v5.0.0/docs/architecture/adr-001-coin-source-tracing.md
+type DenomTrace struct {
+	Path      string
+	BaseDenom string
+}
+
+// FullPath returns the path and the base denom, the denom that is hashed into ibc/<hash>
+func (t DenomTrace) FullPath() string {
+	if t.Path == "" {
+		return t.BaseDenom
+	}
+	return t.Path + "/" + t.BaseDenom
+}
+
+// IsIBCDenom reports whether denom is an IBC voucher, ie: ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2
+func IsIBCDenom(denom string) bool {
+	return strings.HasPrefix(denom, "ibc/")
+}
+
+// DenomTrace resolves an IBC denom(ibc/<hash> or the hash) into its trace with the IBC transfer module
+// https://buf.build/cosmos/ibc/docs/main:ibc.applications.transfer.v1#ibc.applications.transfer.v1.Query.DenomTrace
+func (c *Client) DenomTrace(ctx context.Context, denom string) (*DenomTrace, error) {
+	req := &denomTraceRequest{Hash: strings.TrimPrefix(denom, "ibc/")}
+	res := &denomTraceResponse{}
+	if err := c.conn.Invoke(ctx, "/ibc.applications.transfer.v1.Query/DenomTrace", req, res); err != nil {
+		return nil, fmt.Errorf("query denom trace %s: %w", denom, err)
+	}
+	return &res.DenomTrace, nil
+}
+
+// The messages of the DenomTrace query are encoded by hand so the client does not depend on ibc-go, they are
+// legacy proto messages with Marshal and Unmarshal methods like the gogoproto ones.
+
+// message QueryDenomTraceRequest { string hash = 1; }
+type denomTraceRequest struct {
+	Hash string
+}
+
+func (m *denomTraceRequest) Reset()         { *m = denomTraceRequest{} }
+func (m *denomTraceRequest) String() string { return m.Hash }
+func (*denomTraceRequest) ProtoMessage()    {}
+
+func (m *denomTraceRequest) Marshal() ([]byte, error) {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType) // https://protobuf.dev/programming-guides/encoding/
+	return protowire.AppendString(b, m.Hash), nil
+}
+
+func (m *denomTraceRequest) Unmarshal(b []byte) error {
+	return unmarshalStrings(b, map[protowire.Number]*string{1: &m.Hash})
+}
+
+// message QueryDenomTraceResponse { DenomTrace denom_trace = 1; }
+// message DenomTrace { string path = 1; string base_denom = 2; }
+type denomTraceResponse struct {
+	DenomTrace DenomTrace
+}
+
+func (m *denomTraceResponse) Reset()         { *m = denomTraceResponse{} }
+func (m *denomTraceResponse) String() string { return m.DenomTrace.FullPath() }
+func (*denomTraceResponse) ProtoMessage()    {}
+
+func (m *denomTraceResponse) Marshal() ([]byte, error) {
+	var trace []byte
+	trace = protowire.AppendTag(trace, 1, protowire.BytesType)
+	trace = protowire.AppendString(trace, m.DenomTrace.Path)
+	trace = protowire.AppendTag(trace, 2, protowire.BytesType)
+	trace = protowire.AppendString(trace, m.DenomTrace.BaseDenom)
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, trace), nil
+}
+
+func (m *denomTraceResponse) Unmarshal(b []byte) error {
+	var trace string
+	if err := unmarshalStrings(b, map[protowire.Number]*string{1: &trace}); err != nil {
+		return err
+	}
+	return unmarshalStrings([]byte(trace), map[protowire.Number]*string{1: &m.DenomTrace.Path, 2: &m.DenomTrace.BaseDenom})
+}
+
+// Decode the length delimited fields of b into the strings of fields, the other fields are skipped
+func unmarshalStrings(b []byte, fields map[protowire.Number]*string) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if field, ok := fields[num]; ok && typ == protowire.BytesType {
+			value, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			*field = value
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
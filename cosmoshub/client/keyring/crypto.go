@@ -0,0 +1,76 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters, recommended values for interactive logins https://pkg.go.dev/golang.org/x/crypto/scrypt#Key
+const (
+	kdfScrypt = "scrypt"
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	keyLen    = 32 // AES-256
+	saltLen   = 16
+)
+
+// encrypted data: the key is derived from the passphrase with scrypt and the data is sealed with AES-GCM
+type sealed struct {
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func seal(plaintext []byte, passphrase string) (sealed, error) {
+	s := sealed{KDF: kdfScrypt, Salt: make([]byte, saltLen), N: scryptN, R: scryptR, P: scryptP}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return s, fmt.Errorf("salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, s)
+	if err != nil {
+		return s, err
+	}
+	s.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return s, fmt.Errorf("nonce: %w", err)
+	}
+	s.Ciphertext = gcm.Seal(nil, s.Nonce, plaintext, nil)
+	return s, nil
+}
+
+func open(s sealed, passphrase string) ([]byte, error) {
+	if s.KDF != kdfScrypt {
+		return nil, fmt.Errorf("unsupported kdf %q", s.KDF)
+	}
+	gcm, err := newGCM(passphrase, s)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, s.Nonce, s.Ciphertext, nil)
+	if err != nil {
+		// the authentication fails when the passphrase is not the one used to seal
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase string, s sealed) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), s.Salt, s.N, s.R, s.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("scrypt.Key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
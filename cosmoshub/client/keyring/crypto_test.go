@@ -0,0 +1,44 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte(`{"mnemonic":"abandon abandon about"}`)
+
+	sut, err := seal(plaintext, "passphrase")
+	if err != nil {
+		t.Fatalf("seal error %v", err)
+	}
+	opened, err := open(sut, "passphrase")
+
+	if err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("open should return the sealed plaintext but returns %q, %v", opened, err)
+	}
+	if sut.KDF != kdfScrypt || len(sut.Salt) != saltLen || bytes.Contains(sut.Ciphertext, plaintext) {
+		t.Errorf("the plaintext should be encrypted with a scrypt key but is %+v", sut)
+	}
+	if again, _ := seal(plaintext, "passphrase"); bytes.Equal(again.Salt, sut.Salt) || bytes.Equal(again.Ciphertext, sut.Ciphertext) {
+		t.Errorf("each seal should use a new salt and nonce")
+	}
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	sealed, err := seal([]byte("secret"), "passphrase")
+	if err != nil {
+		t.Fatalf("seal error %v", err)
+	}
+
+	_, sut := open(sealed, "wrong")
+
+	if !errors.Is(sut, ErrWrongPassphrase) {
+		t.Errorf("open with another passphrase should fail with ErrWrongPassphrase but fails %v", sut)
+	}
+	sealed.Ciphertext[0] ^= 1
+	if _, err := open(sealed, "passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("open of a modified ciphertext should fail but fails %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package keyring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultPath is the BIP44 path of the first ATOM account: Account - 0 / External - 0
+// https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki#Purpose
+const DefaultPath = sdk.FullFundraiserPath // "m/44'/118'/0'/0/0"
+
+// Algorithms to compute the address from the public key. Both derive secp256k1 keys, only the address differs.
+const (
+	AlgoSecp256k1    = "secp256k1"     // cosmos chains: ripemd160(sha256(compressed public key))
+	AlgoEthSecp256k1 = "eth_secp256k1" // ethermint chains(coin type 60): keccak256(uncompressed public key)[12:]
+)
+
+// placeholders of a path template
+const (
+	accountPlaceholder = "{account}"
+	indexPlaceholder   = "{index}"
+)
+
+// PathTemplate returns the BIP44 path template for a coin type, ie: 118 ATOM, 330 LUNA, 60 ETH
+// https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+func PathTemplate(coinType uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%s'/0/%s", coinType, accountPlaceholder, indexPlaceholder)
+}
+
+// Path replaces the {account} and {index} placeholders of a template
+func Path(template string, account uint32, index uint32) string {
+	path := strings.ReplaceAll(template, accountPlaceholder, strconv.FormatUint(uint64(account), 10))
+	return strings.ReplaceAll(path, indexPlaceholder, strconv.FormatUint(uint64(index), 10))
+}
+
+// NewMnemonic generates a 24 words BIP39 mnemonic
+// https://pkg.go.dev/github.com/cosmos/go-bip39#section-readme
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", fmt.Errorf("bip39.NewEntropy: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("bip39.NewMnemonic: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// DerivePrivKey derives the secp256k1 private key for path from the mnemonic and the optional BIP39 passphrase
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk@v0.46.0/crypto/hd
+func DerivePrivKey(mnemonic string, bip39Passphrase string, path string) (cryptotypes.PrivKey, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	// Generate seed from mnemonic, the passphrase is known as the 25th word
+	seed := bip39.NewSeed(mnemonic, bip39Passphrase)
+	// Create the master key from the seed and derive it for the path
+	master, ch := hd.ComputeMastersFromSeed(seed)
+	derived, err := hd.DerivePrivateKeyForPath(master, ch, path)
+	if err != nil {
+		return nil, fmt.Errorf("hd.DerivePrivateKeyForPath %s: %w", path, err)
+	}
+	// https://github.com/cosmos/cosmos-sdk/blob/main/crypto/hd/algo.go
+	return hd.Secp256k1.Generate()(derived), nil
+}
+
+// Address computes the address of a public key for an algorithm
+func Address(algo string, pubKey cryptotypes.PubKey) (sdk.AccAddress, error) {
+	switch algo {
+	case AlgoSecp256k1, "":
+		return sdk.AccAddress(pubKey.Address()), nil
+	case AlgoEthSecp256k1:
+		// https://ethereum.github.io/yellowpaper/paper.pdf (284)
+		pub, err := btcec.ParsePubKey(pubKey.Bytes(), btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("parse public key: %w", err)
+		}
+		hash := sha3.NewLegacyKeccak256()
+		hash.Write(pub.SerializeUncompressed()[1:]) // without the 0x04 prefix
+		return sdk.AccAddress(hash.Sum(nil)[12:]), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algo)
+	}
+}
+
+// Derived account for a path
+type Derived struct {
+	Path    string
+	PubKey  cryptotypes.PubKey
+	Address sdk.AccAddress
+}
+
+// MaxDeriveCount limits the accounts derived by a call of DeriveRange
+const MaxDeriveCount = 1000
+
+// DeriveRange derives count accounts starting at index start for the account of a path template, count is at most
+// MaxDeriveCount and the last index must fit in an uint32
+func DeriveRange(mnemonic string, bip39Passphrase string, template string, algo string, account uint32, start uint32, count uint32) ([]Derived, error) {
+	if count > MaxDeriveCount {
+		return nil, fmt.Errorf("derive count %d is over the max %d", count, MaxDeriveCount)
+	}
+	if count > math.MaxUint32-start {
+		return nil, fmt.Errorf("derive count %d from index %d overflows the max index %d", count, start, uint32(math.MaxUint32))
+	}
+	derived := make([]Derived, 0, count)
+	for index := start; index < start+count; index++ {
+		path := Path(template, account, index)
+		privKey, err := DerivePrivKey(mnemonic, bip39Passphrase, path)
+		if err != nil {
+			return nil, err
+		}
+		address, err := Address(algo, privKey.PubKey())
+		if err != nil {
+			return nil, err
+		}
+		derived = append(derived, Derived{Path: path, PubKey: privKey.PubKey(), Address: address})
+	}
+	return derived, nil
+}
@@ -0,0 +1,80 @@
+package keyring
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+// test vectors of the "abandon ... about" mnemonic, the ETH address is the one of https://iancoleman.io/bip39/#english
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		name            string
+		bip39Passphrase string
+		path            string
+		algo            string
+		address         string
+	}{
+		{"ATOM", "", Path(PathTemplate(118), 0, 0), AlgoSecp256k1, "28ff5c6d57d8cfd492b6fb42614536ed648e01fd"}, // cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4
+		{"ATOM index 1", "", Path(PathTemplate(118), 0, 1), AlgoSecp256k1, "90edb6e1c8016bcef766d0ba657f9977ee9f72b6"},
+		{"ATOM BIP39 passphrase", "TREZOR", DefaultPath, AlgoSecp256k1, "525a6ce01168547ef60e0aa7125a340f8ea445c5"},
+		{"LUNA", "", Path(PathTemplate(330), 0, 0), AlgoSecp256k1, "eedab589458fbb16917b36edd992910875bee9a4"}, // terra1amdttz2937a3dytmxmkany53pp6ma6dy4vsllv
+		{"ETH", "", Path(PathTemplate(60), 0, 0), AlgoEthSecp256k1, "9858effd232b4033e47d90003d41ec34ecaeda94"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, err := DerivePrivKey(testMnemonic, tt.bip39Passphrase, tt.path)
+			if err != nil {
+				t.Fatalf("DerivePrivKey error %v", err)
+			}
+
+			sut, err := Address(tt.algo, privKey.PubKey())
+
+			if err != nil || hex.EncodeToString(sut) != tt.address {
+				t.Errorf("the address of %s should be %s but is %x, %v", tt.path, tt.address, sut, err)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	sut := Path("m/44'/330'/{account}'/0/{index}", 2, 7)
+
+	if sut != "m/44'/330'/2'/0/7" {
+		t.Errorf("the placeholders should be replaced but the path is %s", sut)
+	}
+	if template := PathTemplate(118); Path(template, 0, 0) != DefaultPath {
+		t.Errorf("the first path of coin type 118 should be %s but is %s", DefaultPath, Path(template, 0, 0))
+	}
+}
+
+func TestDeriveRange(t *testing.T) {
+	sut, err := DeriveRange(testMnemonic, "", PathTemplate(118), AlgoSecp256k1, 0, 0, 2)
+
+	if err != nil || len(sut) != 2 || sut[1].Path != "m/44'/118'/0'/0/1" {
+		t.Fatalf("DeriveRange should derive the indexes 0 and 1 but derives %+v, %v", sut, err)
+	}
+	if hex.EncodeToString(sut[1].Address) != "90edb6e1c8016bcef766d0ba657f9977ee9f72b6" {
+		t.Errorf("the address of the index 1 should be derived but is %x", sut[1].Address)
+	}
+}
+
+func TestDeriveRangeLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		start uint32
+		count uint32
+	}{
+		{"overflow", math.MaxUint32 - 5, 10},
+		{"over the max count", 0, MaxDeriveCount + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut, err := DeriveRange(testMnemonic, "", PathTemplate(118), AlgoSecp256k1, 0, tt.start, tt.count)
+
+			if err == nil || sut != nil {
+				t.Errorf("DeriveRange from %d count %d should fail but returns %d accounts", tt.start, tt.count, len(sut))
+			}
+		})
+	}
+}
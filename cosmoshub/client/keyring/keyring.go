@@ -0,0 +1,258 @@
+// Package keyring stores mnemonics on disk encrypted with a passphrase. One JSON file is stored per key, the
+// public data(name, public key, derivation path and address algorithm) is readable without the passphrase.
+package keyring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrKeyExists       = errors.New("key already exists")
+	ErrInvalidName     = errors.New("invalid key name, allowed characters: a-z A-Z 0-9 _ -")
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+const fileExt = ".json"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Keyring is a directory with one encrypted file per key
+type Keyring struct {
+	dir string
+}
+
+// Info is the public data of a key
+type Info struct {
+	Name    string
+	Path    string
+	Algo    string
+	PubKey  cryptotypes.PubKey
+	Address sdk.AccAddress
+}
+
+// file stored on disk for each key
+type keyFile struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Algo   string `json:"algo,omitempty"` // address algorithm, AlgoSecp256k1 when empty(files stored before the field)
+	PubKey []byte `json:"pub_key"`        // compressed secp256k1 public key
+	Secret sealed `json:"secret"`
+}
+
+// Secret is the data encrypted inside keyFile.Secret
+type Secret struct {
+	Mnemonic        string `json:"mnemonic"`
+	BIP39Passphrase string `json:"bip39_passphrase,omitempty"`
+}
+
+// Options to derive a key from the mnemonic
+type Options struct {
+	Path            string // HD path, DefaultPath when empty
+	BIP39Passphrase string // optional BIP39 passphrase, empty by default
+	Algo            string // address algorithm, AlgoSecp256k1 when empty
+}
+
+// New opens the keyring stored in dir, the directory is created if it does not exist
+func New(dir string) (*Keyring, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("keyring dir %s: %w", dir, err)
+	}
+	return &Keyring{dir: dir}, nil
+}
+
+// Add generates a new mnemonic and stores it encrypted with passphrase. The mnemonic is not returned, use
+// ExportMnemonic to back it up.
+func (k *Keyring) Add(name string, passphrase string, opts Options) (Info, error) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		return Info{}, err
+	}
+	return k.Import(name, mnemonic, passphrase, opts)
+}
+
+// Import stores an existing mnemonic encrypted with passphrase. Several keys can be imported from the same
+// mnemonic using different paths.
+func (k *Keyring) Import(name string, mnemonic string, passphrase string, opts Options) (Info, error) {
+	if !validName.MatchString(name) {
+		return Info{}, ErrInvalidName
+	}
+	if _, err := os.Stat(k.file(name)); err == nil {
+		return Info{}, fmt.Errorf("%w: %s", ErrKeyExists, name)
+	}
+	if opts.Path == "" {
+		opts.Path = DefaultPath
+	}
+	if opts.Algo == "" {
+		opts.Algo = AlgoSecp256k1
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	privKey, err := DerivePrivKey(mnemonic, opts.BIP39Passphrase, opts.Path)
+	if err != nil {
+		return Info{}, err
+	}
+	file := keyFile{Name: name, Path: opts.Path, Algo: opts.Algo, PubKey: privKey.PubKey().Bytes()}
+	info, err := file.info() // validates the algorithm before storing the key
+	if err != nil {
+		return Info{}, err
+	}
+	plaintext, err := json.Marshal(Secret{Mnemonic: mnemonic, BIP39Passphrase: opts.BIP39Passphrase})
+	if err != nil {
+		return Info{}, err
+	}
+	if file.Secret, err = seal(plaintext, passphrase); err != nil {
+		return Info{}, err
+	}
+	body, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return Info{}, err
+	}
+	// O_EXCL: never overwrite an existing key
+	f, err := os.OpenFile(k.file(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return Info{}, fmt.Errorf("create key %s: %w", name, err)
+	}
+	// a partially written key file would block a new import of the name, it is removed on error
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return Info{}, fmt.Errorf("write key %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return Info{}, fmt.Errorf("close key %s: %w", name, err)
+	}
+	return info, nil
+}
+
+// List returns the keys sorted by name
+func (k *Keyring) List() ([]Info, error) {
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read keyring %s: %w", k.dir, err)
+	}
+	var infos []Info
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		file, err := k.read(strings.TrimSuffix(entry.Name(), fileExt))
+		if err != nil {
+			return nil, err
+		}
+		info, err := file.info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Show returns the public data of a key
+func (k *Keyring) Show(name string) (Info, error) {
+	file, err := k.read(name)
+	if err != nil {
+		return Info{}, err
+	}
+	return file.info()
+}
+
+// ExportMnemonic decrypts and returns the mnemonic of a key
+func (k *Keyring) ExportMnemonic(name string, passphrase string) (string, error) {
+	s, err := k.ExportSecret(name, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return s.Mnemonic, nil
+}
+
+// ExportSecret decrypts and returns the mnemonic and the BIP39 passphrase of a key
+func (k *Keyring) ExportSecret(name string, passphrase string) (Secret, error) {
+	file, err := k.read(name)
+	if err != nil {
+		return Secret{}, err
+	}
+	return file.open(passphrase)
+}
+
+// PrivKey decrypts the mnemonic of a key and derives its private key to sign transactions. It is a secp256k1 key for
+// both algorithms, the ethermint chains verify the signatures of an eth_secp256k1 key differently(keccak256).
+func (k *Keyring) PrivKey(name string, passphrase string) (cryptotypes.PrivKey, error) {
+	file, err := k.read(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := file.open(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return DerivePrivKey(s.Mnemonic, s.BIP39Passphrase, file.Path)
+}
+
+// Delete removes a key from disk
+func (k *Keyring) Delete(name string) error {
+	if _, err := k.read(name); err != nil {
+		return err
+	}
+	return os.Remove(k.file(name))
+}
+
+func (k *Keyring) file(name string) string {
+	return filepath.Join(k.dir, name+fileExt)
+}
+
+func (k *Keyring) read(name string) (keyFile, error) {
+	var file keyFile
+	if !validName.MatchString(name) {
+		return file, ErrInvalidName
+	}
+	body, err := os.ReadFile(k.file(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return file, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	if err != nil {
+		return file, fmt.Errorf("read key %s: %w", name, err)
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return file, fmt.Errorf("parse key %s: %w", name, err)
+	}
+	return file, nil
+}
+
+func (f keyFile) open(passphrase string) (Secret, error) {
+	var s Secret
+	plaintext, err := open(f.Secret, passphrase)
+	if err != nil {
+		return s, fmt.Errorf("key %s: %w", f.Name, err)
+	}
+	err = json.Unmarshal(plaintext, &s)
+	return s, err
+}
+
+func (f keyFile) info() (Info, error) {
+	algo := f.Algo
+	if algo == "" {
+		algo = AlgoSecp256k1
+	}
+	pubKey := &secp256k1.PubKey{Key: f.PubKey}
+	address, err := Address(algo, pubKey)
+	if err != nil {
+		return Info{}, fmt.Errorf("key %s: %w", f.Name, err)
+	}
+	return Info{Name: f.Name, Path: f.Path, Algo: algo, PubKey: pubKey, Address: address}, nil
+}
@@ -0,0 +1,98 @@
+package keyring
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestImportExport(t *testing.T) {
+	sut, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New error %v", err)
+	}
+
+	info, err := sut.Import("alice", testMnemonic, "passphrase", Options{BIP39Passphrase: "25th"})
+	if err != nil {
+		t.Fatalf("Import error %v", err)
+	}
+	secret, err := sut.ExportSecret("alice", "passphrase")
+	if err != nil {
+		t.Fatalf("ExportSecret error %v", err)
+	}
+	privKey, err := sut.PrivKey("alice", "passphrase")
+	if err != nil {
+		t.Fatalf("PrivKey error %v", err)
+	}
+	shown, err := sut.Show("alice")
+	if err != nil {
+		t.Fatalf("Show error %v", err)
+	}
+
+	if secret.Mnemonic != testMnemonic || secret.BIP39Passphrase != "25th" {
+		t.Errorf("ExportSecret should return the imported secret but returns %+v", secret)
+	}
+	if !privKey.PubKey().Equals(info.PubKey) || !shown.Address.Equals(info.Address) || shown.Path != DefaultPath {
+		t.Errorf("the stored key should be the imported one but is %+v", shown)
+	}
+	body, _ := os.ReadFile(sut.file("alice"))
+	if strings.Contains(string(body), "abandon") || strings.Contains(string(body), "25th") {
+		t.Errorf("the key file should not store the secret in plaintext but is %s", body)
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	sut, _ := New(t.TempDir())
+	if _, err := sut.Import("alice", testMnemonic, "passphrase", Options{}); err != nil {
+		t.Fatalf("Import error %v", err)
+	}
+
+	_, exportErr := sut.ExportMnemonic("alice", "wrong")
+	_, privKeyErr := sut.PrivKey("alice", "")
+
+	if !errors.Is(exportErr, ErrWrongPassphrase) || !errors.Is(privKeyErr, ErrWrongPassphrase) {
+		t.Errorf("a wrong passphrase should fail with ErrWrongPassphrase but fails %v, %v", exportErr, privKeyErr)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	sut, _ := New(t.TempDir())
+	sut.Import("alice", testMnemonic, "passphrase", Options{})
+
+	_, exists := sut.Import("alice", testMnemonic, "passphrase", Options{})
+	_, invalidName := sut.Import("../alice", testMnemonic, "passphrase", Options{})
+	_, invalidMnemonic := sut.Import("bob", "abandon about", "passphrase", Options{})
+	_, notFound := sut.Show("bob")
+
+	if !errors.Is(exists, ErrKeyExists) || !errors.Is(invalidName, ErrInvalidName) || !errors.Is(invalidMnemonic, ErrInvalidMnemonic) || !errors.Is(notFound, ErrKeyNotFound) {
+		t.Errorf("Import and Show should fail with the keyring errors but fail %v, %v, %v, %v", exists, invalidName, invalidMnemonic, notFound)
+	}
+}
+
+func TestImportAlgo(t *testing.T) {
+	sut, _ := New(t.TempDir())
+
+	eth, err := sut.Import("eth", testMnemonic, "passphrase", Options{Path: Path(PathTemplate(60), 0, 0), Algo: AlgoEthSecp256k1})
+	if err != nil {
+		t.Fatalf("Import error %v", err)
+	}
+	shown, _ := sut.Show("eth")
+	_, unsupported := sut.Import("ed", testMnemonic, "passphrase", Options{Algo: "ed25519"})
+
+	if eth.Algo != AlgoEthSecp256k1 || shown.Algo != AlgoEthSecp256k1 || shown.Address.String() != eth.Address.String() {
+		t.Errorf("the algorithm should be stored in the key file but the key is %+v", shown)
+	}
+	if hex.EncodeToString(shown.Address) != "9858effd232b4033e47d90003d41ec34ecaeda94" {
+		t.Errorf("the address should be computed with the stored algorithm but is %x", shown.Address)
+	}
+	if unsupported == nil {
+		t.Errorf("an unsupported algorithm should fail")
+	}
+	if _, err := sut.Show("ed"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("a key with an unsupported algorithm should not be stored but Show fails %v", err)
+	}
+}
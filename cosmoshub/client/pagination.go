@@ -0,0 +1,59 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// DefaultPageSize is the number of results requested in each page of the list queries, the nodes cap it(100 by
+// default in the SDK)
+const DefaultPageSize = 100
+
+// PageOption limits the results of the list queries, without options all the pages are requested
+type PageOption func(*pageRequest)
+
+type pageRequest struct {
+	limit   uint64 // max results, 0 is all of them
+	offset  uint64
+	reverse bool
+}
+
+// PageLimit returns at most limit results, 0 returns all of them
+func PageLimit(limit uint64) PageOption {
+	return func(r *pageRequest) { r.limit = limit }
+}
+
+// PageOffset skips the first offset results
+func PageOffset(offset uint64) PageOption {
+	return func(r *pageRequest) { r.offset = offset }
+}
+
+// PageReverse returns the results in descending order
+func PageReverse(reverse bool) PageOption {
+	return func(r *pageRequest) { r.reverse = reverse }
+}
+
+// Request the pages of a list query following the next key until all the results, or the limit of opts, are
+// returned. The offset is only sent in the first request, the next ones continue from the key.
+// https://docs.cosmos.network/v0.46/core/proto-docs.html#cosmos.base.query.v1beta1.PageRequest
+func paginate[T any](opts []PageOption, list func(page *query.PageRequest) ([]T, *query.PageResponse, error)) ([]T, error) {
+	var r pageRequest
+	for _, opt := range opts {
+		opt(&r)
+	}
+	page := &query.PageRequest{Offset: r.offset, Limit: DefaultPageSize, Reverse: r.reverse}
+	var results []T
+	for {
+		if r.limit > 0 && r.limit-uint64(len(results)) < page.Limit {
+			page.Limit = r.limit - uint64(len(results))
+		}
+		items, res, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, items...)
+		if res == nil || len(res.NextKey) == 0 || len(items) == 0 || (r.limit > 0 && uint64(len(results)) >= r.limit) {
+			return results, nil
+		}
+		page = &query.PageRequest{Key: res.NextKey, Limit: page.Limit, Reverse: r.reverse}
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// simulated list of n items, the key of the next page is the index of its first item
+func listOf(n int, requests *[]query.PageRequest) func(page *query.PageRequest) ([]int, *query.PageResponse, error) {
+	return func(page *query.PageRequest) ([]int, *query.PageResponse, error) {
+		*requests = append(*requests, *page)
+		start := int(page.Offset)
+		if len(page.Key) > 0 {
+			start, _ = strconv.Atoi(string(page.Key))
+		}
+		var items []int
+		for i := start; i < n && len(items) < int(page.Limit); i++ {
+			items = append(items, i)
+		}
+		res := &query.PageResponse{}
+		if next := start + len(items); next < n {
+			res.NextKey = []byte(strconv.Itoa(next))
+		}
+		return items, res, nil
+	}
+}
+
+func TestPaginateAllPages(t *testing.T) {
+	var requests []query.PageRequest
+
+	sut, err := paginate(nil, listOf(250, &requests))
+
+	if err != nil {
+		t.Fatalf("paginate error %v", err)
+	}
+	if len(sut) != 250 || sut[249] != 249 {
+		t.Errorf("paginate should return the 250 items but returns %d", len(sut))
+	}
+	if len(requests) != 3 {
+		t.Errorf("paginate should request 3 pages of %d but requests %d", DefaultPageSize, len(requests))
+	}
+	if len(requests) > 1 && (string(requests[1].Key) != "100" || requests[1].Offset != 0) {
+		t.Errorf("the second page should continue from the next key without offset but is %+v", requests[1])
+	}
+}
+
+func TestPaginateLimitAndOffset(t *testing.T) {
+	var requests []query.PageRequest
+
+	sut, err := paginate([]PageOption{PageOffset(10), PageLimit(150)}, listOf(1000, &requests))
+
+	if err != nil {
+		t.Fatalf("paginate error %v", err)
+	}
+	if len(sut) != 150 || sut[0] != 10 || sut[149] != 159 {
+		t.Errorf("paginate should return the items 10 to 159 but returns %d items", len(sut))
+	}
+	if len(requests) != 2 || requests[1].Limit != 50 {
+		t.Errorf("the last page should only request the 50 items left but requests %+v", requests)
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Delegations returns the delegations of delegator with their balance in the bond denom
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/staking/types#QueryClient
+func (c *Client) Delegations(ctx context.Context, delegator sdk.AccAddress, opts ...PageOption) ([]stakingtypes.DelegationResponse, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]stakingtypes.DelegationResponse, *query.PageResponse, error) {
+		res, err := stakingtypes.NewQueryClient(c.conn).DelegatorDelegations(ctx, &stakingtypes.QueryDelegatorDelegationsRequest{DelegatorAddr: c.prefixes.AccAddress(delegator), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.DelegationResponses, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query delegations %s: %w", c.prefixes.AccAddress(delegator), err)
+	}
+	return results, nil
+}
+
+// UnbondingDelegations returns the unbonding delegations of delegator, each entry completes at its CompletionTime
+func (c *Client) UnbondingDelegations(ctx context.Context, delegator sdk.AccAddress, opts ...PageOption) ([]stakingtypes.UnbondingDelegation, error) {
+	results, err := paginate(opts, func(page *query.PageRequest) ([]stakingtypes.UnbondingDelegation, *query.PageResponse, error) {
+		res, err := stakingtypes.NewQueryClient(c.conn).DelegatorUnbondingDelegations(ctx, &stakingtypes.QueryDelegatorUnbondingDelegationsRequest{DelegatorAddr: c.prefixes.AccAddress(delegator), Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.UnbondingResponses, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query unbonding delegations %s: %w", c.prefixes.AccAddress(delegator), err)
+	}
+	return results, nil
+}
+
+// Rewards returns the pending rewards of delegator for each validator and their total
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/distribution/types#QueryClient
+func (c *Client) Rewards(ctx context.Context, delegator sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	res, err := distrtypes.NewQueryClient(c.conn).DelegationTotalRewards(ctx, &distrtypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: c.prefixes.AccAddress(delegator)})
+	if err != nil {
+		return nil, fmt.Errorf("query rewards %s: %w", c.prefixes.AccAddress(delegator), err)
+	}
+	return res, nil
+}
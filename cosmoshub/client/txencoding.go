@@ -0,0 +1,104 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+)
+
+// Transaction encodings: the JSON of the protobuf Tx or its protobuf bytes as base64 or hex
+const (
+	EncodingJSON   = "json"
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+)
+
+// NewTxConfig returns the config to build, sign and encode transactions. The default sign mode is SIGN_MODE_DIRECT,
+// SIGN_MODE_LEGACY_AMINO_JSON is enabled for the members of a multisig: the direct sign bytes include the signer
+// infos and the multisig ones are only known once the signatures are combined. The messages are packed as Any, so
+// the JSON encoding needs them registered in the interface registry.
+// https://docs.cosmos.network/v0.46/core/transactions.html#transaction-generation
+func NewTxConfig() sdkclient.TxConfig {
+	signModes := []signing.SignMode{signing.SignMode_SIGN_MODE_DIRECT, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON} // https://pkg.go.dev/github.com/cosmos/cosmos-sdk@v0.46.0/types/tx/signing#SignMode
+	return authtx.NewTxConfig(NewCodec(), signModes)                                                                 // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/auth/tx#NewTxConfig
+}
+
+// EncodeTx renders the transaction with encoding: json, base64 or hex
+func EncodeTx(txConfig sdkclient.TxConfig, tx sdk.Tx, encoding string) (string, error) {
+	if encoding == EncodingJSON {
+		bz, err := txConfig.TxJSONEncoder()(tx)
+		if err != nil {
+			return "", fmt.Errorf("encode tx json: %w", err)
+		}
+		return string(bz), nil
+	}
+	bz, err := txConfig.TxEncoder()(tx)
+	if err != nil {
+		return "", fmt.Errorf("encode tx: %w", err)
+	}
+	switch encoding {
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(bz), nil
+	case EncodingHex:
+		return strings.ToUpper(hex.EncodeToString(bz)), nil
+	default:
+		return "", fmt.Errorf("unknown tx encoding %q, use json, base64 or hex", encoding)
+	}
+}
+
+// DecodeTx parses a transaction encoded as json, base64 or hex. With an empty encoding it is detected: a JSON
+// object, base64 bytes(the format of the tendermint RPC and the explorers) or hex bytes.
+func DecodeTx(txConfig sdkclient.TxConfig, s string, encoding string) (sdk.Tx, error) {
+	s = strings.TrimSpace(s)
+	if encoding == "" {
+		switch {
+		case strings.HasPrefix(s, "{"):
+			encoding = EncodingJSON
+		case isHex(s):
+			encoding = EncodingHex
+		default:
+			encoding = EncodingBase64
+		}
+	}
+	var bz []byte
+	var err error
+	switch encoding {
+	case EncodingJSON:
+		tx, err := txConfig.TxJSONDecoder()([]byte(s))
+		if err != nil {
+			return nil, fmt.Errorf("decode tx json: %w", err)
+		}
+		return tx, nil
+	case EncodingBase64:
+		bz, err = base64.StdEncoding.DecodeString(s)
+	case EncodingHex:
+		bz, err = hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	default:
+		return nil, fmt.Errorf("unknown tx encoding %q, use json, base64 or hex", encoding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode tx %s: %w", encoding, err)
+	}
+	tx, err := txConfig.TxDecoder()(bz)
+	if err != nil {
+		return nil, fmt.Errorf("decode tx: %w", err)
+	}
+	return tx, nil
+}
+
+// hex strings are also valid base64, a protobuf Tx starts with the body field(0x0A) so its base64 starts with "C"
+// and its hex with "0A"
+func isHex(s string) bool {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s)%2 != 0 || !strings.HasPrefix(strings.ToUpper(s), "0A") {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	tenderminttypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPollInterval is the time between GetTx queries while a transaction is waited
+const DefaultPollInterval = 2 * time.Second
+
+// TxResult is the result of a transaction included in a block, a Code different than 0 means that the
+// delivery failed: the fee is paid and the sequence incremented but the messages are not applied.
+// https://docs.cosmos.network/v0.46/basics/tx-lifecycle.html#delivertx
+type TxResult struct {
+	Hash      string
+	Height    int64
+	Code      uint32
+	Codespace string
+	GasWanted int64
+	GasUsed   int64
+	RawLog    string
+	Logs      sdk.ABCIMessageLogs // events of each message, empty when the delivery failed
+	Events    []abci.Event        // all the events of the transaction, including the ante handler ones(ie: fee)
+}
+
+// Failed reports whether the delivery of the transaction failed
+func (r TxResult) Failed() bool {
+	return r.Code != 0
+}
+
+// GetTx returns the result of a transaction included in a block. The error has the gRPC code NotFound while
+// the transaction is not in a block.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types/tx#ServiceClient
+func (c *Client) GetTx(ctx context.Context, hash string) (*TxResult, error) {
+	res, err := typestx.NewServiceClient(c.conn).GetTx(ctx, &typestx.GetTxRequest{Hash: hash})
+	if err != nil {
+		return nil, fmt.Errorf("get tx %s: %w", hash, err)
+	}
+	return newTxResult(res.TxResponse), nil
+}
+
+// WaitForTx waits until the transaction with hash is included in a block or ctx is done, use a context with a
+// timeout to bound the wait. The tendermint websocket is subscribed when the RPC endpoint is set and GetTx is
+// polled meanwhile, so the wait still works when websockets are unavailable or the transaction was included before
+// subscribing. A failed subscription is not an error: the wait falls back to polling GetTx every poll interval.
+// https://docs.tendermint.com/v0.34/tendermint-core/subscription.html
+func (c *Client) WaitForTx(ctx context.Context, hash string) (*TxResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// a nil channel blocks forever, so without websocket only the polling is done. The subscription error is
+	// ignored on purpose: the polling finds the transaction as well, only later.
+	var events <-chan *TxResult
+	if c.rpcURL != "" {
+		events, _ = c.subscribeTx(ctx, hash)
+	}
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		res, err := c.GetTx(ctx, hash)
+		if err == nil {
+			return res, nil
+		}
+		if status.Code(errors.Unwrap(err)) != codes.NotFound {
+			return nil, err
+		}
+		select {
+		case res := <-events:
+			return res, nil
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for tx %s: %w", hash, ctx.Err())
+		}
+	}
+}
+
+// Subscribe to the Tx event of the transaction, the websocket is stopped when ctx is done or the subscription ends.
+// The results channel is never closed, the wait keeps polling when the subscription ends without a result.
+func (c *Client) subscribeTx(ctx context.Context, hash string) (<-chan *TxResult, error) {
+	rpc, err := rpchttp.New(c.rpcURL, "/websocket") // https://pkg.go.dev/github.com/tendermint/tendermint/rpc/client/http#New
+	if err != nil {
+		return nil, fmt.Errorf("rpchttp.New %s: %w", c.rpcURL, err)
+	}
+	if err := rpc.Start(); err != nil {
+		return nil, fmt.Errorf("rpchttp.Start %s: %w", c.rpcURL, err)
+	}
+
+	// doc for query syntax https://pkg.go.dev/github.com/tendermint/tendermint/libs/pubsub/query
+	query := fmt.Sprintf("tm.event = 'Tx' AND tx.hash = '%s'", hash)
+	subscribed, err := rpc.Subscribe(ctx, "wait-for-tx", query)
+	if err != nil {
+		rpc.Stop()
+		return nil, fmt.Errorf("rpchttp.Subscribe %s: %w", query, err)
+	}
+	results := make(chan *TxResult, 1)
+	go func() {
+		defer rpc.Stop()
+		for {
+			select {
+			case event, ok := <-subscribed:
+				if !ok { // the subscription was cancelled, ie: the websocket disconnected
+					return
+				}
+				if data, ok := event.Data.(tenderminttypes.EventDataTx); ok {
+					results <- newEventTxResult(hash, data)
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+func newTxResult(res *sdk.TxResponse) *TxResult {
+	return &TxResult{
+		Hash:      res.TxHash,
+		Height:    res.Height,
+		Code:      res.Code,
+		Codespace: res.Codespace,
+		GasWanted: res.GasWanted,
+		GasUsed:   res.GasUsed,
+		RawLog:    res.RawLog,
+		Logs:      res.Logs,
+		Events:    res.Events,
+	}
+}
+
+func newEventTxResult(hash string, data tenderminttypes.EventDataTx) *TxResult {
+	deliver := data.Result
+	// the log is the JSON of the message logs when the delivery succeeds and the error otherwise
+	logs, _ := sdk.ParseABCILogs(deliver.Log)
+	return &TxResult{
+		Hash:      hash,
+		Height:    data.Height,
+		Code:      deliver.Code,
+		Codespace: deliver.Codespace,
+		GasWanted: deliver.GasWanted,
+		GasUsed:   deliver.GasUsed,
+		RawLog:    deliver.Log,
+		Logs:      logs,
+		Events:    deliver.Events,
+	}
+}
@@ -3,6 +3,7 @@ module get-balance
 go 1.19
 
 require (
+	cosmoshub/client v0.0.0-00010101000000-000000000000
 	cosmossdk.io/math v1.0.0-beta.3
 	github.com/cosmos/cosmos-sdk v0.46.1
 	github.com/cosmos/go-bip39 v1.0.0
@@ -107,3 +108,5 @@ require (
 )
 
 replace github.com/gogo/protobuf => github.com/regen-network/protobuf v1.3.3-alpha.regen.1
+
+replace cosmoshub/client => ../client
@@ -2,60 +2,85 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
-	"github.com/cosmos/cosmos-sdk/codec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"cosmoshub/client"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 func main() {
+	address := flag.String("address", "cosmos196ax4vc0lwpxndu9dyhvca7jhxp70rmcfhxsrt", "bech32 address of the account")
+	limit := flag.Uint64("limit", 0, "max balances, 0 is all of them")
+	offset := flag.Uint64("offset", 0, "balances skipped from the start of the list")
+	reverse := flag.Bool("reverse", false, "list the balances in descending order")
+	flag.Parse()
+
 	// Read State in mainnet
-	queryMainnetState()
+	if err := queryMainnetState(*address, client.PageLimit(*limit), client.PageOffset(*offset), client.PageReverse(*reverse)); err != nil {
+		log.Printf("Error %s", err)
+		os.Exit(client.ExitCode(err)) // same exit codes as submit-transaction
+	}
 }
 
 // full tutorial https://docs.cosmos.network/v0.46/run-node/interact-node.html
-func queryMainnetState() {
+func queryMainnetState(address string, page ...client.PageOption) error {
 	// create an addr. doc https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types
-	addr, err := sdk.AccAddressFromBech32("cosmos196ax4vc0lwpxndu9dyhvca7jhxp70rmcfhxsrt")
+	addr, err := sdk.AccAddressFromBech32(address)
 	if err != nil {
-		log.Fatalf("Error %s", err)
+		return err
 	}
 	fmt.Println("using", addr.String())
 
-	// Create a connection to the gRPC server. doc https://pkg.go.dev/google.golang.org/grpc
+	// Create a connection to the gRPC server.
 	// more info on how to find a host and port on https://github.com/cosmos/chain-registry/blob/master/cosmoshub/chain.json
 	// if grpc fails while dialing then run the query `curl -X GET "https://rpc.cosmos.network/net_info" -H "accept: application/json"` and use `remote_ip` instead
-	grpcConn, err := grpc.Dial(
-		"54.180.225.240:9090",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec.NewProtoCodec(nil).GRPCCodec())),
-	)
+	c, err := client.Dial("54.180.225.240:9090")
 	if err != nil {
-		log.Fatalf("Error %s", err)
+		return err
 	}
-	defer grpcConn.Close()
-	fmt.Println("open gRPC connection", grpcConn.Target())
+	defer c.Close()
+	fmt.Println("open gRPC connection", c.Conn().Target())
 
-	// This creates a gRPC client to query the x/bank service.
-	bankClient := banktypes.NewQueryClient(grpcConn)
+	// the bank metadata converts the base amounts into the display units, ie: 10000uatom is 0.01atom
+	// https://docs.cosmos.network/v0.46/modules/bank/02_state.html#denomination-metadata
+	denoms, err := c.LoadDenoms(context.Background())
+	if err != nil {
+		fmt.Println("denoms metadata Error", err) // the amounts are printed in the base denoms
+	}
 
 	// query uatom balance for an account
-	bankRes, err := bankClient.Balance(context.Background(), &banktypes.QueryBalanceRequest{Address: addr.String(), Denom: "uatom"})
+	balance, err := c.GetBalance(context.Background(), addr, "uatom")
 	if err != nil {
-		log.Fatalf("Error %s", err)
+		return err
 	}
-	fmt.Println("atom balance", bankRes.String())
+	fmt.Println("atom balance", denoms.Format(balance))
 
-	// query all balances for an account
-	bankAllRes, err := bankClient.AllBalances(context.Background(), &banktypes.QueryAllBalancesRequest{Address: addr.String()})
+	// query all balances for an account, all the pages are requested unless page limits them
+	balances, err := c.GetAllBalances(context.Background(), addr, page...)
 	if err != nil {
-		log.Fatalf("Error %s", err)
+		return err
+	}
+	fmt.Println("all balances")
+	for _, coin := range balances {
+		fmt.Println(" ", denoms.Format(coin), ibcOrigin(c, coin.Denom))
 	}
-	fmt.Println("all balances", bankAllRes.String())
 	fmt.Println("-----------------------------------")
+	return nil
+}
+
+// The base denom and the channels of an IBC voucher(ibc/<hash>), empty for the native denoms
+// https://tutorials.cosmos.network/academy/3-ibc/6-ibc-denoms.html
+func ibcOrigin(c *client.Client, denom string) string {
+	if !client.IsIBCDenom(denom) {
+		return ""
+	}
+	trace, err := c.DenomTrace(context.Background(), denom)
+	if err != nil {
+		return fmt.Sprintf("unknown IBC trace: %s", err)
+	}
+	return fmt.Sprintf("IBC %s from %s", trace.BaseDenom, trace.Path)
 }
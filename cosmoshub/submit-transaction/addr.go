@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"cosmoshub/client"
+)
+
+const addrUsage = `usage: submit-transaction addr convert [flags] <bech32 or hex address>
+
+re-encodes an address between bech32 prefixes and hex, ie: cosmos1... -> osmo1...`
+
+// addr convert [flags] <address>
+func runAddr(args []string) error {
+	if len(args) == 0 || args[0] != "convert" {
+		return errors.New(addrUsage)
+	}
+	flags := flag.NewFlagSet("addr convert", flag.ContinueOnError)
+	prefixes := flags.String("prefix", "", "comma separated bech32 prefixes to encode to, ie: osmo,juno")
+	account := flags.String("bech32-prefix", client.CosmosPrefixes.Account, "account prefix used when -prefix is empty, the address is encoded with the account and validator prefixes")
+	if err := parseArgs(flags, args[1:]); err != nil {
+		return err
+	}
+	address := flags.Arg(0)
+	if address == "" {
+		return errors.New(addrUsage)
+	}
+
+	prefix, bz, err := client.DecodeAddress(address)
+	if err != nil {
+		return err
+	}
+	if prefix == "" {
+		prefix = "hex"
+	}
+	fmt.Printf("%s\t%s\n", prefix, address)
+	fmt.Printf("hex\t0x%X\n", bz)
+
+	// the validator operator address has the same bytes than the account address, the consensus address does not
+	// so it is only encoded when it is requested with -prefix
+	targets := []string{*account, client.NewBech32Prefixes(*account).Validator}
+	if *prefixes != "" {
+		targets = strings.Split(*prefixes, ",")
+	}
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		converted, err := client.ConvertAddress(address, target)
+		if err != nil {
+			return fmt.Errorf("prefix %s: %w", target, err)
+		}
+		fmt.Printf("%s\t%s\n", target, converted)
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const authzUsage = `usage: submit-transaction authz <command> [flags]
+
+commands:
+  grant   the -from key(granter) grants an authorization of -type to -grantee
+  revoke  the -from key(granter) revokes the authorization of -type or -msg-type given to -grantee
+  exec    the -from key(grantee) executes a message of -type on behalf of -granter
+  grants  query the grants given by -granter, received by -grantee or between both
+
+types:
+  generic           any message of -msg-type, ie: /cosmos.gov.v1beta1.MsgVote
+  send              bank sends up to -spend-limit, ie: 1000000uatom
+  delegate          stake authorizations limited to the validators of -allow or excluding the ones of -deny(comma
+  undelegate        separated operator addresses, one of both is required) and optionally to -max-tokens
+  redelegate
+  withdraw-rewards  generic authorization of the distribution MsgWithdrawDelegatorReward
+
+exec messages, from the granter:
+  send              -to <address> -amount n
+  delegate          -validator <address> -amount n
+  undelegate        -validator <address> -amount n
+  redelegate        -validator <address> -dst-validator <address> -amount n
+  withdraw-rewards  -validator <address>`
+
+// defaultGrantExpiration is the expiration of the grants when -expiration is not set
+const defaultGrantExpiration = 30 * 24 * time.Hour
+
+var authzCommands = map[string]func(args []string) error{
+	"grant":  runAuthzGrant,
+	"revoke": runAuthzRevoke,
+	"exec":   runAuthzExec,
+	"grants": runAuthzGrants,
+}
+
+// authz <command> [flags]
+func runAuthz(args []string) error {
+	if len(args) == 0 {
+		return errors.New(authzUsage)
+	}
+	command, ok := authzCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("authz: unknown command %q\n%s", args[0], authzUsage)
+	}
+	return command(args[1:])
+}
+
+// Flags of the authz commands, the staking ones are used by exec
+type authzParams struct {
+	stakingParams
+	granter    string
+	grantee    string
+	authType   string
+	msgType    string
+	spendLimit string
+	maxTokens  string
+	allow      string
+	deny       string
+	expiration time.Duration
+}
+
+func newAuthzFlagSet(name string, p *authzParams) *flag.FlagSet {
+	flags := newStakingFlagSet(name, &p.stakingParams)
+	flags.StringVar(&p.granter, "granter", "", "granter, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.grantee, "grantee", "", "grantee, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.authType, "type", "", "authorization type: generic, send, delegate, undelegate, redelegate or withdraw-rewards")
+	flags.StringVar(&p.msgType, "msg-type", "", "message type URL of a generic authorization, ie: /cosmos.gov.v1beta1.MsgVote")
+	flags.StringVar(&p.spendLimit, "spend-limit", "", "spend limit of a send authorization, ie: 1000000uatom")
+	flags.StringVar(&p.maxTokens, "max-tokens", "", "max tokens of a stake authorization, unlimited when empty")
+	flags.StringVar(&p.allow, "allow", "", "comma separated validators allowed by a stake authorization")
+	flags.StringVar(&p.deny, "deny", "", "comma separated validators denied by a stake authorization")
+	flags.DurationVar(&p.expiration, "expiration", defaultGrantExpiration, "time until the grant expires, 0 never expires")
+	return flags
+}
+
+// authz grant -grantee <key or address> -type <type> [flags]
+func runAuthzGrant(args []string) error {
+	return runAuthzTx("authz grant", args, func(p authzParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error) {
+		if p.grantee == "" {
+			return nil, errors.New("missing -grantee")
+		}
+		grantee, err := resolveAddress(kr, p.grantee, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		authorization, err := newAuthorization(p, net)
+		if err != nil {
+			return nil, err
+		}
+		var expiration *time.Time
+		if p.expiration > 0 {
+			t := time.Now().Add(p.expiration)
+			expiration = &t
+		}
+		fmt.Println("Grant", client.DescribeAuthorization(authorization), "to", net.Bech32.AccAddress(grantee), "expiration", expiration)
+		// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/authz/spec/03_messages.md#msggrant
+		msg, err := authz.NewMsgGrant(from.address, grantee, authorization, expiration)
+		if err != nil {
+			return nil, err
+		}
+		return []sdk.Msg{msg}, nil
+	})
+}
+
+// authz revoke -grantee <key or address> -type <type> | -msg-type <url> [flags]
+func runAuthzRevoke(args []string) error {
+	return runAuthzTx("authz revoke", args, func(p authzParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error) {
+		if p.grantee == "" {
+			return nil, errors.New("missing -grantee")
+		}
+		grantee, err := resolveAddress(kr, p.grantee, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		msgType := p.msgType
+		if msgType == "" {
+			var err error
+			if msgType, err = authorizationMsgType(p.authType); err != nil {
+				return nil, err
+			}
+		}
+		// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/authz/spec/03_messages.md#msgrevoke
+		msg := authz.NewMsgRevoke(from.address, grantee, msgType)
+		return []sdk.Msg{&msg}, nil
+	})
+}
+
+// authz exec -granter <key or address> -type <type> [flags]
+func runAuthzExec(args []string) error {
+	return runAuthzTx("authz exec", args, func(p authzParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error) {
+		if p.granter == "" {
+			return nil, errors.New("missing -granter")
+		}
+		granter, err := resolveAddress(kr, p.granter, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := execMsg(p, net, kr, granter)
+		if err != nil {
+			return nil, err
+		}
+		// the grantee signs and pays the fee, the messages are executed as the granter
+		// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/authz/spec/03_messages.md#msgexec
+		exec := authz.NewMsgExec(from.address, []sdk.Msg{msg})
+		return []sdk.Msg{&exec}, nil
+	})
+}
+
+// Parse the flags, load the signer, build the messages with newMsgs and send them in a transaction
+func runAuthzTx(name string, args []string, newMsgs func(p authzParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error)) error {
+	var p authzParams
+	flags := newAuthzFlagSet(name, &p)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	msgs, err := newMsgs(p, net, kr, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := sendAndWait(c, net, from, p.dryRun, p.wait, msgs...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// The authorization of -type with its limits
+// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/authz/spec/01_concepts.md#built-in-authorizations
+func newAuthorization(p authzParams, net network) (authz.Authorization, error) {
+	switch p.authType {
+	case "generic":
+		if p.msgType == "" {
+			return nil, errors.New("a generic authorization needs -msg-type")
+		}
+		return authz.NewGenericAuthorization(p.msgType), nil
+	case "withdraw-rewards":
+		return authz.NewGenericAuthorization(sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{})), nil
+	case "send":
+		spendLimit, err := sdk.ParseCoinsNormalized(p.spendLimit) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#ParseCoinsNormalized
+		if err != nil {
+			return nil, fmt.Errorf("-spend-limit: %w", err)
+		}
+		if spendLimit.Empty() {
+			return nil, errors.New("a send authorization needs -spend-limit")
+		}
+		return banktypes.NewSendAuthorization(spendLimit), nil
+	case "delegate", "undelegate", "redelegate":
+		allowed, err := parseValAddresses(p.allow, net.Bech32)
+		if err != nil {
+			return nil, fmt.Errorf("-allow: %w", err)
+		}
+		denied, err := parseValAddresses(p.deny, net.Bech32)
+		if err != nil {
+			return nil, fmt.Errorf("-deny: %w", err)
+		}
+		// NewStakeAuthorization needs exactly one of the lists, the error is clearer with the flag names
+		if (len(allowed) == 0) == (len(denied) == 0) {
+			return nil, errors.New("a stake authorization needs either -allow or -deny validators")
+		}
+		var maxTokens *sdk.Coin // nil means no limit
+		if p.maxTokens != "" {
+			coin, err := sdk.ParseCoinNormalized(p.maxTokens)
+			if err != nil {
+				return nil, fmt.Errorf("-max-tokens: %w", err)
+			}
+			maxTokens = &coin
+		}
+		authzType := map[string]stakingtypes.AuthorizationType{
+			"delegate":   stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE,
+			"undelegate": stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_UNDELEGATE,
+			"redelegate": stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_REDELEGATE,
+		}[p.authType]
+		// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/staking/types#NewStakeAuthorization
+		return stakingtypes.NewStakeAuthorization(allowed, denied, authzType, maxTokens)
+	default:
+		return nil, fmt.Errorf("unknown authorization -type %q", p.authType)
+	}
+}
+
+// The message type URL authorized by each type, it identifies the grant to revoke
+func authorizationMsgType(authType string) (string, error) {
+	switch authType {
+	case "send":
+		return sdk.MsgTypeURL(&banktypes.MsgSend{}), nil
+	case "delegate":
+		return sdk.MsgTypeURL(&stakingtypes.MsgDelegate{}), nil
+	case "undelegate":
+		return sdk.MsgTypeURL(&stakingtypes.MsgUndelegate{}), nil
+	case "redelegate":
+		return sdk.MsgTypeURL(&stakingtypes.MsgBeginRedelegate{}), nil
+	case "withdraw-rewards":
+		return sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{}), nil
+	default:
+		return "", fmt.Errorf("unknown authorization -type %q, use -msg-type", authType)
+	}
+}
+
+// The message of -type executed on behalf of the granter
+func execMsg(p authzParams, net network, kr *keyring.Keyring, granter sdk.AccAddress) (sdk.Msg, error) {
+	coin, err := parseAmount(net, p.amount)
+	if err != nil {
+		return nil, fmt.Errorf("-amount: %w", err)
+	}
+	switch p.authType {
+	case "send":
+		to, err := resolveAddress(kr, p.to, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		return banktypes.NewMsgSend(granter, to, sdk.NewCoins(coin)), nil
+	case "delegate", "undelegate", "withdraw-rewards":
+		validator, err := net.Bech32.ParseValAddress(p.validator)
+		if err != nil {
+			return nil, fmt.Errorf("-validator: %w", err)
+		}
+		switch p.authType {
+		case "delegate":
+			return stakingtypes.NewMsgDelegate(granter, validator, coin), nil
+		case "undelegate":
+			return stakingtypes.NewMsgUndelegate(granter, validator, coin), nil
+		}
+		return distrtypes.NewMsgWithdrawDelegatorReward(granter, validator), nil
+	case "redelegate":
+		src, err := net.Bech32.ParseValAddress(p.validator)
+		if err != nil {
+			return nil, fmt.Errorf("-validator: %w", err)
+		}
+		dst, err := net.Bech32.ParseValAddress(p.dstValidator)
+		if err != nil {
+			return nil, fmt.Errorf("-dst-validator: %w", err)
+		}
+		return stakingtypes.NewMsgBeginRedelegate(granter, src, dst, coin), nil
+	default:
+		return nil, fmt.Errorf("unknown exec -type %q", p.authType)
+	}
+}
+
+func parseValAddresses(addresses string, prefixes client.Bech32Prefixes) ([]sdk.ValAddress, error) {
+	var parsed []sdk.ValAddress
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+		valAddress, err := prefixes.ParseValAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", address, err)
+		}
+		parsed = append(parsed, valAddress)
+	}
+	return parsed, nil
+}
+
+// The granter and grantee of a grants query, an empty one is nil
+func resolveGrantPair(kr *keyring.Keyring, granter string, grantee string, prefixes client.Bech32Prefixes) (sdk.AccAddress, sdk.AccAddress, error) {
+	var granterAddress, granteeAddress sdk.AccAddress
+	var err error
+	if granter != "" {
+		if granterAddress, err = resolveAddress(kr, granter, prefixes); err != nil {
+			return nil, nil, err
+		}
+	}
+	if grantee != "" {
+		if granteeAddress, err = resolveAddress(kr, grantee, prefixes); err != nil {
+			return nil, nil, err
+		}
+	}
+	return granterAddress, granteeAddress, nil
+}
+
+// authz grants [-granter <key or address>] [-grantee <key or address>] [-msg-type <url>] [-limit n] [-offset n] [-reverse] [flags]
+func runAuthzGrants(args []string) error {
+	var p authzParams
+	flags := newAuthzFlagSet("authz grants", &p)
+	page := newPageFlags(flags)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if p.granter == "" && p.grantee == "" {
+		return errors.New("authz grants: missing -granter or -grantee")
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	granter, grantee, err := resolveGrantPair(kr, p.granter, p.grantee, net.Bech32)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var grants []*authz.GrantAuthorization
+	switch {
+	case granter != nil && grantee != nil:
+		pairGrants, err := c.Grants(ctx, granter, grantee, p.msgType, page.options()...)
+		if err != nil {
+			return err
+		}
+		for _, grant := range pairGrants {
+			grants = append(grants, &authz.GrantAuthorization{
+				Granter:       net.Bech32.AccAddress(granter),
+				Grantee:       net.Bech32.AccAddress(grantee),
+				Authorization: grant.Authorization,
+				Expiration:    grant.Expiration,
+			})
+		}
+	case granter != nil:
+		if grants, err = c.GranterGrants(ctx, granter, page.options()...); err != nil {
+			return err
+		}
+	default:
+		if grants, err = c.GranteeGrants(ctx, grantee, page.options()...); err != nil {
+			return err
+		}
+	}
+	if len(grants) == 0 {
+		fmt.Println("No grants")
+	}
+	for _, grant := range grants {
+		authorization, err := c.UnpackAuthorization(grant.Authorization)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\t%s\texpiration %v\n", grant.Granter, grant.Grantee, client.DescribeAuthorization(authorization), grant.Expiration)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestNewAuthorizationStake(t *testing.T) {
+	validator := testNetwork.Bech32.ValAddress(sdk.ValAddress([]byte("validator___________")))
+	tests := []struct {
+		name  string
+		allow string
+		deny  string
+		valid bool
+	}{
+		{"allow", validator, "", true},
+		{"deny", "", validator, true},
+		{"neither allow nor deny", "", "", false},
+		{"allow and deny", validator, validator, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := authzParams{authType: "delegate", allow: test.allow, deny: test.deny, maxTokens: "1000uatom"}
+
+			sut, err := newAuthorization(p, testNetwork)
+
+			if !test.valid {
+				if err == nil {
+					t.Errorf("newAuthorization should fail but returns %v", sut)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newAuthorization error %v", err)
+			}
+			stake, ok := sut.(*stakingtypes.StakeAuthorization)
+			if !ok || stake.MaxTokens.String() != "1000uatom" || stake.AuthorizationType != stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE {
+				t.Errorf("newAuthorization should return a delegate authorization of 1000uatom but returns %v", sut)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const batchUsage = `usage: submit-transaction batch -file <payments.csv|payments.json> [flags]
+
+sends the payments of the file from the -from key. The CSV file needs a header with the columns
+recipient,amount,denom,memo and the JSON file is a list of {"recipient","amount","denom","memo"}.
+The denom is optional(the network denom by default) and so is the memo.`
+
+// defaultMaxMsgs limits the messages of a transaction, big transactions can exceed the max gas of a block
+const defaultMaxMsgs = 50
+
+// A row of the batch file, the amount is in the base denom, ie: 10000uatom = 0.01 ATOM
+type payment struct {
+	Recipient string `json:"recipient"`
+	Amount    int64  `json:"amount"`
+	Denom     string `json:"denom"`
+	Memo      string `json:"memo"`
+}
+
+// Payments packed in the same transaction, all of them share the memo
+type chunk struct {
+	memo     string
+	payments []payment
+}
+
+// batch -file payments.csv [-max-msgs 50] [-multisend] [flags]
+func runBatch(args []string) error {
+	var p params
+	flags := newFlagSet("batch", &p)
+	file := flags.String("file", "", "CSV or JSON file with the payments")
+	maxMsgs := flags.Int("max-msgs", defaultMaxMsgs, "max payments per transaction, the payments are split in several transactions")
+	multiSend := flags.Bool("multisend", false, "pack the payments of a transaction in one MsgMultiSend instead of a MsgSend per payment")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New(batchUsage)
+	}
+	if *maxMsgs <= 0 {
+		return fmt.Errorf("invalid -max-msgs %d", *maxMsgs)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	payments, err := loadPayments(*file, net.Denom)
+	if err != nil {
+		return err
+	}
+	chunks := splitPayments(payments, *maxMsgs)
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// the sequence is incremented locally, the node accepts the next transaction once the previous one passed CheckTx
+	queue := newSenderQueue(c, net, from, p.dryRun, 1)
+	defer queue.close()
+	for i, ch := range chunks {
+		msgs, err := paymentMsgs(from.address, ch.payments, net.Bech32, *multiSend)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", i+1, err)
+		}
+		fmt.Printf("Chunk %d/%d: %d payments, %d messages, memo %q\n", i+1, len(chunks), len(ch.payments), len(msgs), ch.memo)
+		txRes, err := queue.send(ch.memo, msgs...)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", i+1, err)
+		}
+		if p.dryRun {
+			continue
+		}
+		if err := client.CheckTxResponse(txRes); err != nil {
+			// the payments of the next chunks could depend on this one, stop here
+			return fmt.Errorf("chunk %d: %w", i+1, err)
+		}
+		fmt.Printf("Chunk %d/%d tx hash %s\n", i+1, len(chunks), txRes.TxHash)
+	}
+	return nil
+}
+
+// Read the payments from a JSON file(.json extension) or a CSV file, empty denoms are set to denom
+func loadPayments(path string, denom string) ([]payment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var payments []payment
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(f).Decode(&payments); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+	} else if payments, err = readPaymentsCSV(f); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("%s has no payments", path)
+	}
+	for i := range payments {
+		if payments[i].Denom == "" {
+			payments[i].Denom = denom
+		}
+		if payments[i].Recipient == "" || payments[i].Amount <= 0 {
+			return nil, fmt.Errorf("%s payment %d: recipient and a positive amount are required", path, i+1)
+		}
+		if err := sdk.ValidateDenom(payments[i].Denom); err != nil { // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#ValidateDenom
+			return nil, fmt.Errorf("%s payment %d: %w", path, i+1, err)
+		}
+	}
+	return payments, nil
+}
+
+// The columns are found by the header names so their order does not matter
+func readPaymentsCSV(r io.Reader) ([]payment, error) {
+	reader := csv.NewReader(r) // https://pkg.go.dev/encoding/csv
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"recipient", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var payments []payment
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return payments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		amount, err := strconv.ParseInt(field(record, "amount"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: amount: %w", line, err)
+		}
+		payments = append(payments, payment{
+			Recipient: field(record, "recipient"),
+			Amount:    amount,
+			Denom:     field(record, "denom"),
+			Memo:      field(record, "memo"),
+		})
+	}
+}
+
+// The memo belongs to the transaction, so the payments are grouped by memo(keeping the order of the file) and each
+// group is split in chunks of at most maxMsgs payments
+func splitPayments(payments []payment, maxMsgs int) []chunk {
+	var memos []string
+	groups := map[string][]payment{}
+	for _, p := range payments {
+		if _, ok := groups[p.Memo]; !ok {
+			memos = append(memos, p.Memo)
+		}
+		groups[p.Memo] = append(groups[p.Memo], p)
+	}
+	var chunks []chunk
+	for _, memo := range memos {
+		group := groups[memo]
+		for len(group) > 0 {
+			n := maxMsgs
+			if len(group) < n {
+				n = len(group)
+			}
+			chunks = append(chunks, chunk{memo: memo, payments: group[:n]})
+			group = group[n:]
+		}
+	}
+	return chunks
+}
+
+// A MsgSend per payment or a single MsgMultiSend with one input(the sender) and an output per payment
+// https://docs.cosmos.network/v0.46/modules/bank/03_messages.html
+func paymentMsgs(from sdk.AccAddress, payments []payment, prefixes client.Bech32Prefixes, multiSend bool) ([]sdk.Msg, error) {
+	var msgs []sdk.Msg
+	var outputs []banktypes.Output
+	total := sdk.NewCoins()
+	for _, p := range payments {
+		to, err := prefixes.ParseAccAddress(p.Recipient)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", p.Recipient, err)
+		}
+		coins := sdk.NewCoins(sdk.NewCoin(p.Denom, math.NewInt(p.Amount)))
+		if multiSend {
+			outputs = append(outputs, banktypes.NewOutput(to, coins))
+			total = total.Add(coins...)
+			continue
+		}
+		msgs = append(msgs, banktypes.NewMsgSend(from, to, coins))
+	}
+	if multiSend {
+		inputs := []banktypes.Input{banktypes.NewInput(from, total)}
+		msgs = append(msgs, banktypes.NewMsgMultiSend(inputs, outputs)) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/bank/types#NewMsgMultiSend
+	}
+	return msgs, nil
+}
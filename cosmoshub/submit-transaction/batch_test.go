@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestSplitPayments(t *testing.T) {
+	payments := []payment{
+		{Recipient: "a", Memo: "salary"},
+		{Recipient: "b", Memo: "bonus"},
+		{Recipient: "c", Memo: "salary"},
+		{Recipient: "d", Memo: "salary"},
+		{Recipient: "e", Memo: "bonus"},
+		{Recipient: "f"},
+	}
+
+	sut := splitPayments(payments, 2)
+
+	expected := []struct {
+		memo       string
+		recipients string
+	}{{"salary", "ac"}, {"salary", "d"}, {"bonus", "be"}, {"", "f"}}
+	if len(sut) != len(expected) {
+		t.Fatalf("splitPayments should return %d chunks but returns %+v", len(expected), sut)
+	}
+	for i, chunk := range sut {
+		var recipients string
+		for _, p := range chunk.payments {
+			recipients += p.Recipient
+		}
+		if chunk.memo != expected[i].memo || recipients != expected[i].recipients {
+			t.Errorf("chunk %d should be %q with %s but is %q with %s", i, expected[i].memo, expected[i].recipients, chunk.memo, recipients)
+		}
+	}
+}
+
+func TestReadPaymentsCSV(t *testing.T) {
+	body := "memo, Amount ,RECIPIENT,denom\n" +
+		"salary,10000,cosmos1a,\n" +
+		"bonus, 5,cosmos1b,uosmo\n"
+
+	sut, err := readPaymentsCSV(strings.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("readPaymentsCSV error %v", err)
+	}
+	expected := []payment{
+		{Recipient: "cosmos1a", Amount: 10000, Memo: "salary"},
+		{Recipient: "cosmos1b", Amount: 5, Denom: "uosmo", Memo: "bonus"},
+	}
+	if len(sut) != len(expected) || sut[0] != expected[0] || sut[1] != expected[1] {
+		t.Errorf("the columns should be found by their header names but the payments are %+v", sut)
+	}
+}
+
+func TestReadPaymentsCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"missing amount", "recipient,denom\ncosmos1a,uatom\n", "missing amount column"},
+		{"missing recipient", "amount,memo\n10,salary\n", "missing recipient column"},
+		{"invalid amount", "recipient,amount\ncosmos1a,10\ncosmos1b,1atom\n", "line 3: amount"},
+		{"empty", "", "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readPaymentsCSV(strings.NewReader(tt.body))
+
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("readPaymentsCSV should fail with %q but fails %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPaymentMsgs(t *testing.T) {
+	from := newTestAccount().address
+	alice, bob := newTestAccount().address, newTestAccount().address
+	payments := []payment{
+		{Recipient: client.CosmosPrefixes.AccAddress(alice), Amount: 100, Denom: "uatom"},
+		{Recipient: client.CosmosPrefixes.AccAddress(bob), Amount: 50, Denom: "uatom"},
+		{Recipient: client.CosmosPrefixes.AccAddress(alice), Amount: 7, Denom: "uosmo"},
+	}
+
+	sends, err := paymentMsgs(from, payments, client.CosmosPrefixes, false)
+	if err != nil {
+		t.Fatalf("paymentMsgs error %v", err)
+	}
+	multiSend, err := paymentMsgs(from, payments, client.CosmosPrefixes, true)
+	if err != nil {
+		t.Fatalf("paymentMsgs multisend error %v", err)
+	}
+
+	if len(sends) != 3 {
+		t.Errorf("a MsgSend per payment should be returned but %d messages are", len(sends))
+	}
+	if len(multiSend) != 1 {
+		t.Fatalf("a single MsgMultiSend should be returned but %d messages are", len(multiSend))
+	}
+	msg, ok := multiSend[0].(*banktypes.MsgMultiSend)
+	if !ok || len(msg.Inputs) != 1 || len(msg.Outputs) != 3 || msg.Inputs[0].Address != client.CosmosPrefixes.AccAddress(from) {
+		t.Fatalf("the MsgMultiSend should have the sender input and an output per payment but is %v", multiSend[0])
+	}
+	outputs := sdk.NewCoins()
+	for _, output := range msg.Outputs {
+		outputs = outputs.Add(output.Coins...)
+	}
+	if !msg.Inputs[0].Coins.IsEqual(outputs) || msg.Inputs[0].Coins.String() != "150uatom,7uosmo" {
+		t.Errorf("the input %s should be the sum of the outputs %s", msg.Inputs[0].Coins, outputs)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		t.Errorf("the MsgMultiSend should be valid but fails %v", err)
+	}
+	if _, err := paymentMsgs(from, []payment{{Recipient: "osmo1invalid", Amount: 1, Denom: "uatom"}}, client.CosmosPrefixes, true); err == nil {
+		t.Errorf("an invalid recipient should fail")
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"gopkg.in/yaml.v3"
+)
+
+// network profile: where to connect and how to build transactions for a chain
+type network struct {
+	GrpcURL  string `yaml:"grpc"`      // gRPC endpoint used for queries and broadcast
+	RpcURL   string `yaml:"rpc"`       // tendermint RPC endpoint used to subscribe to events
+	ChainID  string `yaml:"chain-id"`  // chain id used while signing
+	Denom    string `yaml:"denom"`     // base denom used for amounts and fees
+	Fee      int64  `yaml:"fee"`       // the maximum amount the user is willing to pay in fees, 0 computes it from the gas price
+	GasLimit uint64 `yaml:"gas-limit"` // max units of gas, 0 simulates the transaction to estimate it
+
+	GasPrice      string  `yaml:"gas-price"`      // ie: 0.0025uatom, required when fee is 0
+	GasAdjustment float64 `yaml:"gas-adjustment"` // multiplier applied to the simulated gas
+
+	Bech32 client.Bech32Prefixes `yaml:"bech32"` // address prefixes, validator and consensus are derived from account when empty
+
+	TxTimeout time.Duration `yaml:"tx-timeout"` // ie: 90s, max time waiting for a transaction to be included in a block, defaultTxTimeout when 0
+
+	FeeGranter string `yaml:"fee-granter"` // bech32 address that pays the fees with a feegrant allowance, the signer pays when empty
+}
+
+// time waiting for a transaction when the profile has no tx-timeout, a block takes ~6s on the cosmoshub
+const defaultTxTimeout = time.Minute
+
+// The parsed fee-granter, nil when the signer pays the fees
+func (n network) feeGranter() (sdk.AccAddress, error) {
+	if n.FeeGranter == "" {
+		return nil, nil
+	}
+	address, err := n.Bech32.ParseAccAddress(n.FeeGranter)
+	if err != nil {
+		return nil, fmt.Errorf("fee-granter: %w", err)
+	}
+	return address, nil
+}
+
+// config file layout, ie:
+//
+//	network: theta
+//	networks:
+//	  theta:
+//	    grpc: rpc.sentry-01.theta-testnet.polypore.xyz:9090
+//	    ...
+type config struct {
+	Network  string             `yaml:"network"`
+	Networks map[string]network `yaml:"networks"`
+}
+
+// built-in profiles, used when there is no config file or a profile is not present in it
+// chain registry https://github.com/cosmos/chain-registry/blob/master/cosmoshub/chain.json
+// testnets https://github.com/cosmos/testnets/tree/master/v7-theta/public-testnet
+func defaultConfig() config {
+	return config{
+		Network: "theta",
+		Networks: map[string]network{
+			"mainnet": {
+				GrpcURL:       "54.180.225.240:9090",
+				RpcURL:        "https://rpc.cosmos.network:443",
+				ChainID:       "cosmoshub-4",
+				Denom:         "uatom",
+				Bech32:        client.CosmosPrefixes,
+				GasPrice:      "0.0025uatom",
+				GasAdjustment: client.DefaultGasAdjustment,
+			},
+			"theta": {
+				GrpcURL:       "rpc.sentry-01.theta-testnet.polypore.xyz:9090",
+				RpcURL:        "https://rpc.sentry-01.theta-testnet.polypore.xyz:26657",
+				ChainID:       "theta-testnet-001",
+				Denom:         "uatom",
+				Bech32:        client.CosmosPrefixes,
+				GasPrice:      "0.0025uatom",
+				GasAdjustment: client.DefaultGasAdjustment,
+			},
+			"local": {
+				GrpcURL:       "localhost:9090",
+				RpcURL:        "http://localhost:26657",
+				ChainID:       "localnet",
+				Denom:         "uatom",
+				Bech32:        client.CosmosPrefixes,
+				GasPrice:      "0.0025uatom",
+				GasAdjustment: client.DefaultGasAdjustment,
+			},
+		},
+	}
+}
+
+// Load the config file on top of the built-in profiles. A missing file is not an error, the defaults are used.
+// The fields of a built-in profile set in the file replace its defaults, the others are kept: a profile with only
+// grpc uses the built-in chain-id, denom and gas settings.
+func loadConfig(path string) (config, error) {
+	cfg := defaultConfig()
+	body, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	// the profiles are kept as nodes to decode them over the built-in ones
+	var file struct {
+		Network  string               `yaml:"network"`
+		Networks map[string]yaml.Node `yaml:"networks"`
+	}
+	if err := yaml.Unmarshal(body, &file); err != nil { // https://pkg.go.dev/gopkg.in/yaml.v3#Unmarshal
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if file.Network != "" {
+		cfg.Network = file.Network
+	}
+	for name, node := range file.Networks {
+		profile := cfg.Networks[name]                 // zero for a new profile
+		if err := node.Decode(&profile); err != nil { // only the fields present in the node are set
+			return cfg, fmt.Errorf("parsing %s network %s: %w", path, name, err)
+		}
+		cfg.Networks[name] = profile
+	}
+	return cfg, nil
+}
+
+// command line parameters
+type params struct {
+	configFile string
+	network    string
+	keyringDir string
+	from       string
+	to         string
+	amount     string
+	dryRun     bool
+	waitFunded time.Duration
+	overrides  network // only the flags set by the user are applied over the profile
+}
+
+func parseFlags(args []string) (params, *flag.FlagSet, error) {
+	var p params
+	flags := newFlagSet("submit-transaction", &p)
+	flags.DurationVar(&p.waitFunded, "wait-funded", 0, "poll the balance of from until it has the amount, at most this time(ie: 10m), 0 does not wait")
+	err := parseArgs(flags, args)
+	return p, flags, err
+}
+
+// errUsage wraps the errors of the command line flags
+var errUsage = errors.New("invalid flags")
+
+// Parse the flags of a command, -h returns flag.ErrHelp and the other errors are errUsage
+func parseArgs(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return fmt.Errorf("%w: %s", errUsage, err)
+}
+
+// Flags shared by the commands that send transactions
+func newFlagSet(name string, p *params) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError) // https://pkg.go.dev/flag#FlagSet
+	flags.StringVar(&p.configFile, "config", "config.yaml", "path to the YAML config file with the network profiles")
+	flags.StringVar(&p.network, "network", "", "network profile to use (mainnet, theta, local or any profile in the config file)")
+	flags.StringVar(&p.keyringDir, "keyring-dir", "keyring", "directory where the encrypted keys are stored")
+	flags.StringVar(&p.from, "from", "from", "name of the key in the keyring that signs the transaction")
+	flags.StringVar(&p.to, "to", "to", "receiver, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.amount, "amount", "10000", "amount in the network denom(10000 = 10000uatom = 0.01 ATOM) or in a display denom(0.01atom)")
+	flags.StringVar(&p.overrides.GrpcURL, "grpc", "", "gRPC endpoint, overrides the profile")
+	flags.StringVar(&p.overrides.RpcURL, "rpc", "", "tendermint RPC endpoint, overrides the profile")
+	flags.StringVar(&p.overrides.ChainID, "chain-id", "", "chain id, overrides the profile")
+	flags.StringVar(&p.overrides.Denom, "denom", "", "denom, overrides the profile")
+	flags.Int64Var(&p.overrides.Fee, "fee", 0, "fee amount in the network denom, 0 computes it from the gas price, overrides the profile")
+	flags.Uint64Var(&p.overrides.GasLimit, "gas", 0, "gas limit, 0 simulates the transaction, overrides the profile")
+	flags.StringVar(&p.overrides.GasPrice, "gas-price", "", "gas price(ie: 0.0025uatom), overrides the profile")
+	flags.Float64Var(&p.overrides.GasAdjustment, "gas-adjustment", 0, "multiplier of the simulated gas, overrides the profile")
+	flags.BoolVar(&p.dryRun, "dry-run", false, "print the gas and fee estimation without broadcasting the transaction")
+	flags.StringVar(&p.overrides.Bech32.Account, "bech32-prefix", "", "bech32 account prefix(ie: osmo), overrides the profile")
+	flags.DurationVar(&p.overrides.TxTimeout, "timeout", 0, "max time waiting for the transaction(ie: 90s), overrides the profile")
+	flags.StringVar(&p.overrides.FeeGranter, "fee-granter", "", "bech32 address that pays the fees with its feegrant allowance, overrides the profile")
+	return flags
+}
+
+// Flags of the list queries, all the pages are requested when they are not set
+type pageParams struct {
+	limit   uint64
+	offset  uint64
+	reverse bool
+}
+
+func newPageFlags(flags *flag.FlagSet) *pageParams {
+	var p pageParams
+	flags.Uint64Var(&p.limit, "limit", 0, "max results of the list, 0 is all of them")
+	flags.Uint64Var(&p.offset, "offset", 0, "results skipped from the start of the list")
+	flags.BoolVar(&p.reverse, "reverse", false, "list the results in descending order")
+	return &p
+}
+
+func (p pageParams) options() []client.PageOption {
+	return []client.PageOption{client.PageLimit(p.limit), client.PageOffset(p.offset), client.PageReverse(p.reverse)}
+}
+
+// Resolve the network profile to use: config file + selected profile + flags set by the user
+func resolveNetwork(p params, flags *flag.FlagSet) (network, error) {
+	cfg, err := loadConfig(p.configFile)
+	if err != nil {
+		return network{}, err
+	}
+	name := p.network
+	if name == "" {
+		name = cfg.Network
+	}
+	net, ok := cfg.Networks[name]
+	if !ok {
+		return network{}, fmt.Errorf("unknown network profile %q", name)
+	}
+	flags.Visit(func(f *flag.Flag) { // only visits the flags that have been set https://pkg.go.dev/flag#FlagSet.Visit
+		switch f.Name {
+		case "grpc":
+			net.GrpcURL = p.overrides.GrpcURL
+		case "rpc":
+			net.RpcURL = p.overrides.RpcURL
+		case "chain-id":
+			net.ChainID = p.overrides.ChainID
+		case "denom":
+			net.Denom = p.overrides.Denom
+		case "fee":
+			net.Fee = p.overrides.Fee
+		case "gas":
+			net.GasLimit = p.overrides.GasLimit
+		case "gas-price":
+			net.GasPrice = p.overrides.GasPrice
+		case "gas-adjustment":
+			net.GasAdjustment = p.overrides.GasAdjustment
+		case "bech32-prefix":
+			net.Bech32 = client.NewBech32Prefixes(p.overrides.Bech32.Account)
+		case "timeout":
+			net.TxTimeout = p.overrides.TxTimeout
+		case "fee-granter":
+			net.FeeGranter = p.overrides.FeeGranter
+		}
+	})
+	net.Bech32 = net.Bech32.WithDefaults()
+	if net.TxTimeout <= 0 {
+		net.TxTimeout = defaultTxTimeout
+	}
+	fmt.Fprintln(os.Stderr, "Network profile", name)
+	return net, nil
+}
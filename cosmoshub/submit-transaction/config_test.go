@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cosmoshub/client"
+)
+
+func TestLoadConfigMergesProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	body := `network: devnet
+networks:
+  theta:
+    grpc: localhost:19090
+    gas-adjustment: 2
+  devnet:
+    grpc: localhost:9090
+    chain-id: devnet-1
+    denom: stake
+`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile error %v", err)
+	}
+
+	sut, err := loadConfig(path)
+
+	if err != nil {
+		t.Fatalf("loadConfig error %v", err)
+	}
+	theta := sut.Networks["theta"]
+	defaults := defaultConfig().Networks["theta"]
+	if theta.GrpcURL != "localhost:19090" || theta.GasAdjustment != 2 {
+		t.Errorf("the fields of the file should override the theta profile but it is %+v", theta)
+	}
+	if theta.ChainID != defaults.ChainID || theta.Denom != defaults.Denom || theta.GasPrice != defaults.GasPrice || theta.RpcURL != defaults.RpcURL || theta.Bech32 != client.CosmosPrefixes {
+		t.Errorf("the fields missing in the file should keep the built-in theta values but it is %+v", theta)
+	}
+	if devnet := sut.Networks["devnet"]; sut.Network != "devnet" || devnet.ChainID != "devnet-1" || devnet.Denom != "stake" || devnet.GasPrice != "" {
+		t.Errorf("a new profile should only have the fields of the file but it is %+v", devnet)
+	}
+	if mainnet := sut.Networks["mainnet"]; mainnet != defaultConfig().Networks["mainnet"] {
+		t.Errorf("the profiles missing in the file should be the built-in ones but mainnet is %+v", mainnet)
+	}
+}
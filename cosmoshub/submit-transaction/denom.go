@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The coin of an -amount: an integer in the network denom(10000 is 10000uatom), an amount of a base denom
+// (10000uatom) or a decimal amount of a display denom(0.01atom) converted with the bank metadata of the chain
+func parseAmount(net network, amount string) (sdk.Coin, error) {
+	// the metadata is only queried for the denoms that are not the network one
+	coin, err := client.NewDenoms(nil).ParseAmount(amount, net.Denom)
+	if !errors.Is(err, client.ErrUnknownDenom) {
+		return coin, err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	defer c.Close()
+	denoms, err := c.LoadDenoms(context.Background())
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	return denoms.ParseAmount(amount, net.Denom)
+}
+
+// The metadata of the denoms to print the amounts in their display units, nil when it can not be queried so the
+// amounts are printed in the base denoms
+func loadDenoms(c *client.Client) *client.Denoms {
+	denoms, err := c.LoadDenoms(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Denoms metadata error", err)
+		return nil
+	}
+	return denoms
+}
+
+// Render a denom: the display unit and exponent of its metadata or the trace of an IBC denom
+func describeDenom(c *client.Client, denoms *client.Denoms, denom string) string {
+	if client.IsIBCDenom(denom) {
+		trace, err := c.DenomTrace(context.Background(), denom)
+		if err != nil {
+			return fmt.Sprintf("%s unknown trace: %s", denom, err)
+		}
+		return fmt.Sprintf("%s base %s path %s", denom, trace.BaseDenom, trace.Path)
+	}
+	metadata, _, ok := denoms.Lookup(denom)
+	if !ok {
+		return fmt.Sprintf("%s without metadata", denom)
+	}
+	_, exponent, _ := denoms.Lookup(metadata.Display)
+	return fmt.Sprintf("%s base %s display %s exponent %d", denom, metadata.Base, metadata.Display, exponent)
+}
+
+// denoms [-denom <denom>] [flags]
+func runDenoms(args []string) error {
+	var p params
+	flags := newFlagSet("denoms", &p)
+	denom := flags.String("denom", "", "denom to describe(ie: uatom, atom or ibc/<hash>), all the denoms with metadata when empty")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	metadata, err := c.DenomsMetadata(context.Background())
+	if err != nil {
+		return fmt.Errorf("denoms: %w", err)
+	}
+	denoms := client.NewDenoms(metadata)
+	if *denom != "" {
+		fmt.Println(describeDenom(c, denoms, *denom))
+		return nil
+	}
+	if len(metadata) == 0 {
+		fmt.Println("No denoms metadata")
+	}
+	for _, m := range metadata {
+		fmt.Println(describeDenom(c, denoms, m.Base))
+	}
+	return nil
+}
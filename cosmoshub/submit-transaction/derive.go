@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+)
+
+// HD path flags shared by the commands that derive keys
+type hdPath struct {
+	template *string
+	coinType *uint
+	account  *uint
+	index    *uint
+}
+
+func pathFlags(flags *flag.FlagSet) hdPath {
+	return hdPath{
+		template: flags.String("path", "", "HD path template, {account} and {index} are replaced, ie: m/44'/118'/{account}'/0/{index}"),
+		coinType: flags.Uint("coin-type", 118, "BIP44 coin type used when -path is empty, ie: 118 ATOM, 330 LUNA, 60 ETH"),
+		account:  flags.Uint("account", 0, "BIP44 account"),
+		index:    flags.Uint("index", 0, "BIP44 address index"),
+	}
+}
+
+func (p hdPath) pathTemplate() string {
+	if *p.template != "" {
+		return *p.template
+	}
+	return keyring.PathTemplate(uint32(*p.coinType))
+}
+
+func (p hdPath) path() string {
+	return keyring.Path(p.pathTemplate(), uint32(*p.account), uint32(*p.index))
+}
+
+// derive [flags]: list the addresses derived from a mnemonic, it is useful to verify paths offline against
+// known test vectors, ie: https://iancoleman.io/bip39/#english
+func runDerive(args []string) error {
+	flags := flag.NewFlagSet("derive", flag.ContinueOnError)
+	dir := flags.String("keyring-dir", "keyring", "directory where the encrypted keys are stored")
+	key := flags.String("key", "", "derive from the mnemonic of a key in the keyring instead of -mnemonic")
+	mnemonic := flags.String("mnemonic", "", "mnemonic to derive from, prompted when empty and -key is not set")
+	withBIP39Passphrase := flags.Bool("bip39-passphrase", false, "prompt for the optional BIP39 passphrase")
+	algo := flags.String("algo", keyring.AlgoSecp256k1, "address algorithm: secp256k1 or eth_secp256k1")
+	count := flags.Uint("count", 5, "number of addresses to derive starting at -index, at most 1000")
+	prefix := flags.String("bech32-prefix", client.CosmosPrefixes.Account, "bech32 account prefix of the addresses, ie: osmo, evmos")
+	path := pathFlags(flags)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *count > keyring.MaxDeriveCount {
+		return fmt.Errorf("derive: -count %d is over the max %d", *count, keyring.MaxDeriveCount)
+	}
+
+	var secret keyring.Secret
+	var err error
+	switch {
+	case *key != "":
+		kr, err := keyring.New(*dir)
+		if err != nil {
+			return err
+		}
+		passphrase, err := readPassphrase(false)
+		if err != nil {
+			return err
+		}
+		if secret, err = kr.ExportSecret(*key, passphrase); err != nil {
+			return err
+		}
+	case *mnemonic != "":
+		secret.Mnemonic = *mnemonic
+	default:
+		if secret.Mnemonic, err = readSecret("Mnemonic: "); err != nil {
+			return err
+		}
+	}
+	if *withBIP39Passphrase {
+		if secret.BIP39Passphrase, err = readSecret("BIP39 passphrase: "); err != nil {
+			return err
+		}
+	}
+
+	derived, err := keyring.DeriveRange(secret.Mnemonic, secret.BIP39Passphrase, path.pathTemplate(), *algo,
+		uint32(*path.account), uint32(*path.index), uint32(*count))
+	if err != nil {
+		return err
+	}
+	for _, d := range derived {
+		fmt.Printf("%s\t%s\t0x%x\t%X\n", d.Path, client.NewBech32Prefixes(*prefix).AccAddress(d.Address), []byte(d.Address), d.PubKey.Bytes())
+	}
+	return nil
+}
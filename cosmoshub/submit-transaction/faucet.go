@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const faucetUsage = `usage: submit-transaction faucet -from <funded key> [-listen localhost:8000] [-amount 10000] [flags]
+
+serves an HTTP faucet that sends -amount from the -from key to the requesters:
+  POST /fund    {"address":"cosmos1..."}(or the address form value), responds {"address","amount","tx_hash"}
+  GET  /status  the faucet address, the amount, the daily cap and the amount sent today
+an address gets -address-limit transfers and an IP -ip-limit transfers every -window, and the faucet sends at most
+-daily-cap each day(UTC). The rejected requests respond {"error"} with status 400, 429 or 503.`
+
+// default transfers of an IP in a window, several developers or CI jobs can share it
+const defaultFaucetIPLimit = 5
+
+// the requests of the limits
+var (
+	errAddressLimit = errors.New("request limit of the address reached, try again later")
+	errIPLimit      = errors.New("request limit of the IP reached, try again later")
+	errDailyCap     = errors.New("daily cap of the faucet reached, try again tomorrow")
+)
+
+// Rate limits of the faucet: transfers per address and per IP in a sliding window and the amount sent each day
+type faucetLimiter struct {
+	mu           sync.Mutex
+	window       time.Duration
+	addressLimit int
+	ipLimit      int
+	dailyCap     math.Int
+	addresses    map[string][]time.Time // address -> times of its transfers in the window
+	ips          map[string][]time.Time
+	day          string
+	sent         math.Int // sent in day
+	now          func() time.Time
+}
+
+func newFaucetLimiter(window time.Duration, addressLimit int, ipLimit int, dailyCap math.Int) *faucetLimiter {
+	return &faucetLimiter{
+		window:       window,
+		addressLimit: addressLimit,
+		ipLimit:      ipLimit,
+		dailyCap:     dailyCap,
+		addresses:    map[string][]time.Time{},
+		ips:          map[string][]time.Time{},
+		sent:         math.ZeroInt(),
+		now:          time.Now,
+	}
+}
+
+// Reserve a transfer of amount to address requested from ip, release undoes it when the transfer is not sent
+func (l *faucetLimiter) reserve(address string, ip string, amount math.Int) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if day := now.UTC().Format("2006-01-02"); day != l.day {
+		l.day, l.sent = day, math.ZeroInt()
+		sweepTimes(l.addresses, now.Add(-l.window))
+		sweepTimes(l.ips, now.Add(-l.window))
+	}
+	l.addresses[address] = recentTimes(l.addresses[address], now.Add(-l.window))
+	l.ips[ip] = recentTimes(l.ips[ip], now.Add(-l.window))
+	switch {
+	case len(l.addresses[address]) >= l.addressLimit:
+		return nil, errAddressLimit
+	case len(l.ips[ip]) >= l.ipLimit:
+		return nil, errIPLimit
+	case l.sent.Add(amount).GT(l.dailyCap):
+		return nil, errDailyCap
+	}
+	l.addresses[address] = append(l.addresses[address], now)
+	l.ips[ip] = append(l.ips[ip], now)
+	l.sent = l.sent.Add(amount)
+	day := l.day
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		setTimes(l.addresses, address, removeTime(l.addresses[address], now))
+		setTimes(l.ips, ip, removeTime(l.ips[ip], now))
+		if l.day == day {
+			l.sent = l.sent.Sub(amount)
+		}
+	}, nil
+}
+
+// The amount sent today
+func (l *faucetLimiter) sentToday() math.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.now().UTC().Format("2006-01-02") != l.day {
+		return math.ZeroInt()
+	}
+	return l.sent
+}
+
+// Remove the keys without times after since, once a day the requesters that did not come back are forgotten
+func sweepTimes(times map[string][]time.Time, since time.Time) {
+	for key, keyTimes := range times {
+		setTimes(times, key, recentTimes(keyTimes, since))
+	}
+}
+
+// Set the times of key, the key is deleted when there are none
+func setTimes(times map[string][]time.Time, key string, keyTimes []time.Time) {
+	if len(keyTimes) == 0 {
+		delete(times, key)
+		return
+	}
+	times[key] = keyTimes
+}
+
+// The times after since, they are in ascending order
+func recentTimes(times []time.Time, since time.Time) []time.Time {
+	for i, t := range times {
+		if t.After(since) {
+			return times[i:]
+		}
+	}
+	return nil
+}
+
+func removeTime(times []time.Time, t time.Time) []time.Time {
+	for i := range times {
+		if times[i].Equal(t) {
+			return append(times[:i:i], times[i+1:]...)
+		}
+	}
+	return times
+}
+
+// HTTP faucet, send transfers amount to an address and returns the CheckTx result
+type faucet struct {
+	address        string
+	amount         sdk.Coin
+	prefixes       client.Bech32Prefixes
+	limiter        *faucetLimiter
+	send           func(to sdk.AccAddress) (*sdk.TxResponse, error)
+	trustForwarded bool
+}
+
+// faucet -from <funded key> [-listen addr] [-window 24h] [-address-limit 1] [-ip-limit 5] [-daily-cap amount] [flags]
+func runFaucet(args []string) error {
+	var p params
+	flags := newFlagSet("faucet", &p)
+	listen := flags.String("listen", "localhost:8000", "address where the HTTP faucet listens")
+	window := flags.Duration("window", 24*time.Hour, "window of the request limits")
+	addressLimit := flags.Int("address-limit", 1, "transfers to an address in a window")
+	ipLimit := flags.Int("ip-limit", defaultFaucetIPLimit, "transfers requested from an IP in a window")
+	dailyCap := flags.String("daily-cap", "", "max amount sent each day(UTC), 100 times -amount when empty")
+	trustForwarded := flags.Bool("trust-forwarded", false, "limit the IP added by the proxy to X-Forwarded-For(the last one), only behind a trusted proxy")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *window <= 0 || *addressLimit <= 0 || *ipLimit <= 0 {
+		return fmt.Errorf("faucet: -window, -address-limit and -ip-limit must be positive\n%s", faucetUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+	amount, err := parseAmount(net, p.amount)
+	if err != nil {
+		return fmt.Errorf("faucet: -amount %w", err)
+	}
+	capAmount := amount.Amount.MulRaw(100)
+	if *dailyCap != "" {
+		capCoin, err := parseAmount(net, *dailyCap)
+		if err != nil {
+			return fmt.Errorf("faucet: -daily-cap %w", err)
+		}
+		if capCoin.Denom != amount.Denom {
+			return fmt.Errorf("faucet: -daily-cap %s is not in the denom of -amount %s", capCoin, amount)
+		}
+		capAmount = capCoin.Amount
+	}
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	// the requests are served concurrently, the queue signs the transfers in order with the next sequence
+	queue := newSenderQueue(c, net, from, p.dryRun, 16)
+	defer queue.close()
+
+	f := &faucet{
+		address:        from.bech32(),
+		amount:         amount,
+		prefixes:       net.Bech32,
+		limiter:        newFaucetLimiter(*window, *addressLimit, *ipLimit, capAmount),
+		trustForwarded: *trustForwarded,
+		send: func(to sdk.AccAddress) (*sdk.TxResponse, error) {
+			return queue.send("faucet", newMsgSend(from.address, to, amount))
+		},
+	}
+	server := &http.Server{Addr: *listen, Handler: f.handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	// serve until it is interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	fmt.Fprintln(os.Stderr, "Faucet", f.address, "sends", amount, "listening on", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("faucet: %w", err)
+	}
+	return nil
+}
+
+func (f *faucet) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fund", f.fund)
+	mux.HandleFunc("/status", f.status)
+	return mux
+}
+
+// POST /fund: validate the address, reserve the transfer in the limiter and send it
+func (f *faucet) fund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	var req struct {
+		Address string `json:"address"`
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+			return
+		}
+	} else {
+		req.Address = r.FormValue("address")
+	}
+	to, err := f.prefixes.ParseAccAddress(strings.TrimSpace(req.Address))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid address %q", req.Address)})
+		return
+	}
+	address := f.prefixes.AccAddress(to)
+	release, err := f.limiter.reserve(address, f.clientIP(r), f.amount.Amount)
+	switch {
+	case errors.Is(err, errDailyCap):
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		return
+	}
+	txRes, err := f.send(to)
+	if err == nil {
+		err = client.CheckTxResponse(txRes)
+	}
+	if err != nil {
+		release()
+		fmt.Fprintln(os.Stderr, "Faucet transfer to", address, "error", err)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "transfer failed, try again later"})
+		return
+	}
+	hash := ""
+	if txRes != nil { // nil on dry run
+		hash = txRes.TxHash
+	}
+	fmt.Fprintln(os.Stderr, "Faucet sent", f.amount, "to", address, "tx", hash)
+	writeJSON(w, http.StatusOK, map[string]string{"address": address, "amount": f.amount.String(), "tx_hash": hash})
+}
+
+// GET /status
+func (f *faucet) status(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"address":    f.address,
+		"amount":     f.amount.String(),
+		"daily_cap":  sdk.NewCoin(f.amount.Denom, f.limiter.dailyCap).String(),
+		"sent_today": sdk.NewCoin(f.amount.Denom, f.limiter.sentToday()).String(),
+	})
+}
+
+// The IP of the requester, the last address of X-Forwarded-For when the faucet runs behind a trusted proxy: it is
+// the one added by the proxy, the previous ones are sent by the requester and can be spoofed
+func (f *faucet) clientIP(r *http.Request) string {
+	if forwarded := r.Header.Values("X-Forwarded-For"); f.trustForwarded && len(forwarded) > 0 {
+		last := forwarded[len(forwarded)-1]
+		if i := strings.LastIndex(last, ","); i >= 0 {
+			last = last[i+1:]
+		}
+		return strings.TrimSpace(last)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
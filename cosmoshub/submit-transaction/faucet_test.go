@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestFaucetLimiter(t *testing.T) {
+	now := testNow
+	sut := newFaucetLimiter(time.Hour, 1, 2, math.NewInt(250))
+	sut.now = func() time.Time { return now }
+
+	_, first := sut.reserve("alice", "10.0.0.1", math.NewInt(100))
+	_, sameAddress := sut.reserve("alice", "10.0.0.2", math.NewInt(100))
+	_, second := sut.reserve("bob", "10.0.0.1", math.NewInt(100))
+	_, sameIP := sut.reserve("carol", "10.0.0.1", math.NewInt(100))
+	_, overCap := sut.reserve("carol", "10.0.0.3", math.NewInt(100))
+	now = now.Add(time.Hour)
+	_, afterWindow := sut.reserve("alice", "10.0.0.1", math.NewInt(50))
+
+	if first != nil || second != nil || afterWindow != nil {
+		t.Errorf("the requests in the limits should be reserved but fail %v, %v, %v", first, second, afterWindow)
+	}
+	if !errors.Is(sameAddress, errAddressLimit) || !errors.Is(sameIP, errIPLimit) || !errors.Is(overCap, errDailyCap) {
+		t.Errorf("the requests over the limits should fail but fail %v, %v, %v", sameAddress, sameIP, overCap)
+	}
+	if sent := sut.sentToday(); sent.Int64() != 250 {
+		t.Errorf("sentToday should be 250 but is %s", sent)
+	}
+}
+
+func TestFaucetLimiterRelease(t *testing.T) {
+	now := testNow
+	sut := newFaucetLimiter(time.Hour, 1, 1, math.NewInt(100))
+	sut.now = func() time.Time { return now }
+
+	release, _ := sut.reserve("alice", "10.0.0.1", math.NewInt(100))
+	release()
+	_, again := sut.reserve("alice", "10.0.0.1", math.NewInt(100))
+	now = now.Add(24 * time.Hour)
+	_, nextDay := sut.reserve("bob", "10.0.0.2", math.NewInt(100))
+
+	if again != nil {
+		t.Errorf("a released request should not count in the limits but fails %v", again)
+	}
+	if nextDay != nil {
+		t.Errorf("the daily cap should reset the next day but fails %v", nextDay)
+	}
+}
+
+func TestFaucetLimiterSweep(t *testing.T) {
+	now := testNow
+	sut := newFaucetLimiter(time.Hour, 1, 1, math.NewInt(1000))
+	sut.now = func() time.Time { return now }
+
+	sut.reserve("alice", "10.0.0.1", math.NewInt(100))
+	release, _ := sut.reserve("bob", "10.0.0.2", math.NewInt(100))
+	release()
+	afterRelease := len(sut.addresses)
+	now = now.Add(24 * time.Hour)
+	sut.reserve("carol", "10.0.0.3", math.NewInt(100))
+
+	if afterRelease != 1 {
+		t.Errorf("a released request should remove its address but there are %d", afterRelease)
+	}
+	if len(sut.addresses) != 1 || len(sut.ips) != 1 || sut.addresses["carol"] == nil || sut.ips["10.0.0.3"] == nil {
+		t.Errorf("the next day only the requests in the window should be kept but are %v and %v", sut.addresses, sut.ips)
+	}
+}
+
+func TestFaucetFund(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	queue := newSenderQueue(c, testNetwork, from, false, 4)
+	defer queue.close()
+	amount := sdk.NewInt64Coin("uatom", 10000)
+	sut := &faucet{
+		address:  from.bech32(),
+		amount:   amount,
+		prefixes: client.CosmosPrefixes,
+		limiter:  newFaucetLimiter(time.Hour, 1, 5, math.NewInt(100000)),
+		send: func(to sdk.AccAddress) (*sdk.TxResponse, error) {
+			return queue.send("faucet", newMsgSend(from.address, to, amount))
+		},
+	}
+	server := httptest.NewServer(sut.handler())
+	defer server.Close()
+	to := sdk.AccAddress([]byte("requester___________"))
+	body := `{"address":"` + client.CosmosPrefixes.AccAddress(to) + `"}`
+
+	res, err := http.Post(server.URL+"/fund", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /fund error %v", err)
+	}
+	var funded map[string]string
+	json.NewDecoder(res.Body).Decode(&funded)
+	res.Body.Close()
+	again, err := http.Post(server.URL+"/fund", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /fund error %v", err)
+	}
+	again.Body.Close()
+	invalid, err := http.PostForm(server.URL+"/fund", map[string][]string{"address": {"osmo1invalid"}})
+	if err != nil {
+		t.Fatalf("POST /fund error %v", err)
+	}
+	invalid.Body.Close()
+
+	if res.StatusCode != http.StatusOK || funded["amount"] != "10000uatom" || funded["tx_hash"] == "" {
+		t.Fatalf("the first request should be funded but responds %d %v", res.StatusCode, funded)
+	}
+	txs := node.Txs()
+	if len(txs) != 1 {
+		t.Fatalf("the faucet should broadcast 1 transaction but broadcasts %d", len(txs))
+	}
+	send, ok := txs[0].GetMsgs()[0].(*banktypes.MsgSend)
+	if !ok || send.ToAddress != client.CosmosPrefixes.AccAddress(to) || send.Amount.String() != "10000uatom" {
+		t.Errorf("the transaction should send 10000uatom to the requester but is %v", txs[0].GetMsgs())
+	}
+	if again.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("the second request of the address should respond 429 but responds %d", again.StatusCode)
+	}
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Errorf("an invalid address should respond 400 but responds %d", invalid.StatusCode)
+	}
+}
+
+func TestFaucetClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustForwarded bool
+		forwarded      []string
+		ip             string
+	}{
+		{"remote address", false, nil, "192.0.2.1"},
+		{"untrusted header", false, []string{"10.0.0.9"}, "192.0.2.1"},
+		{"proxy", true, []string{"10.0.0.9"}, "10.0.0.9"},
+		{"spoofed entries", true, []string{"1.2.3.4, 5.6.7.8,10.0.0.9"}, "10.0.0.9"},
+		{"spoofed header", true, []string{"1.2.3.4", "10.0.0.9"}, "10.0.0.9"},
+		{"no header", true, nil, "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := &faucet{trustForwarded: tt.trustForwarded}
+			r := httptest.NewRequest(http.MethodPost, "/fund", nil)
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			ip := sut.clientIP(r)
+
+			if ip != tt.ip {
+				t.Errorf("the client IP should be %s but is %s", tt.ip, ip)
+			}
+		})
+	}
+}
+
+func TestFaucetSpoofedForwardedFor(t *testing.T) {
+	sent := 0
+	sut := &faucet{
+		amount:         sdk.NewInt64Coin("uatom", 10000),
+		prefixes:       client.CosmosPrefixes,
+		limiter:        newFaucetLimiter(time.Hour, 1, 1, math.NewInt(100000)),
+		trustForwarded: true,
+		send: func(to sdk.AccAddress) (*sdk.TxResponse, error) {
+			sent++
+			return &sdk.TxResponse{TxHash: "AB"}, nil
+		},
+	}
+	fund := func(spoofed string, requester string) int {
+		body := `{"address":"` + client.CosmosPrefixes.AccAddress(sdk.AccAddress([]byte(requester))) + `"}`
+		r := httptest.NewRequest(http.MethodPost, "/fund", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Forwarded-For", spoofed+", 10.0.0.9")
+		w := httptest.NewRecorder()
+		sut.handler().ServeHTTP(w, r)
+		return w.Code
+	}
+
+	first := fund("1.1.1.1", "requester-1_________")
+	second := fund("2.2.2.2", "requester-2_________")
+
+	if first != http.StatusOK || second != http.StatusTooManyRequests || sent != 1 {
+		t.Errorf("a spoofed X-Forwarded-For should not bypass the IP limit but the requests respond %d, %d", first, second)
+	}
+}
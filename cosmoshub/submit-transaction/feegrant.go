@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+const feegrantUsage = `usage: submit-transaction feegrant <command> [flags]
+
+commands:
+  grant       the -from key(granter) pays the fees of -grantee up to -spend-limit until -expiration
+  revoke      the -from key(granter) revokes the allowance given to -grantee
+  allowances  query the allowances given by -granter, received by -grantee or between both
+
+allowances:
+  basic       -spend-limit and -expiration, the spend limit is unlimited when empty
+  periodic    -period and -period-limit on top of the basic ones, the period limit is restored every period
+  allowed     -allowed-msgs limits any of them to the comma separated message type URLs
+
+the grantee uses the allowance with -fee-granter <granter address> or fee-granter in the network profile, it can
+transact with a zero balance`
+
+var feegrantCommands = map[string]func(args []string) error{
+	"grant":      runFeegrantGrant,
+	"revoke":     runFeegrantRevoke,
+	"allowances": runFeegrantAllowances,
+}
+
+// feegrant <command> [flags]
+func runFeegrant(args []string) error {
+	if len(args) == 0 {
+		return errors.New(feegrantUsage)
+	}
+	command, ok := feegrantCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("feegrant: unknown command %q\n%s", args[0], feegrantUsage)
+	}
+	return command(args[1:])
+}
+
+// Flags of the feegrant commands
+type feegrantParams struct {
+	params
+	granter     string
+	grantee     string
+	spendLimit  string
+	expiration  time.Duration
+	period      time.Duration
+	periodLimit string
+	allowedMsgs string
+	wait        bool
+}
+
+func newFeegrantFlagSet(name string, p *feegrantParams) *flag.FlagSet {
+	flags := newFlagSet(name, &p.params)
+	flags.StringVar(&p.granter, "granter", "", "granter, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.grantee, "grantee", "", "grantee, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.spendLimit, "spend-limit", "", "max fees paid for the grantee, ie: 1000000uatom, unlimited when empty")
+	flags.DurationVar(&p.expiration, "expiration", defaultGrantExpiration, "time until the allowance expires, 0 never expires")
+	flags.DurationVar(&p.period, "period", 0, "period of a periodic allowance, ie: 24h, 0 is a basic allowance")
+	flags.StringVar(&p.periodLimit, "period-limit", "", "max fees paid for the grantee in each period of a periodic allowance")
+	flags.StringVar(&p.allowedMsgs, "allowed-msgs", "", "comma separated message type URLs whose fees are paid, all when empty")
+	flags.BoolVar(&p.wait, "wait", false, "wait until the transaction is included in a block")
+	return flags
+}
+
+// feegrant grant -grantee <key or address> [-spend-limit coins] [-period d -period-limit coins] [flags]
+func runFeegrantGrant(args []string) error {
+	return runFeegrantTx("feegrant grant", args, func(p feegrantParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error) {
+		if p.grantee == "" {
+			return nil, errors.New("missing -grantee")
+		}
+		grantee, err := resolveAddress(kr, p.grantee, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		allowance, err := newAllowance(p, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if err := allowance.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		fmt.Println("Grant allowance", client.DescribeAllowance(allowance), "to", net.Bech32.AccAddress(grantee))
+		// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/feegrant/spec/03_messages.md#msggrantallowance
+		msg, err := feegrant.NewMsgGrantAllowance(allowance, from.address, grantee)
+		if err != nil {
+			return nil, err
+		}
+		return []sdk.Msg{msg}, nil
+	})
+}
+
+// feegrant revoke -grantee <key or address> [flags]
+func runFeegrantRevoke(args []string) error {
+	return runFeegrantTx("feegrant revoke", args, func(p feegrantParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error) {
+		if p.grantee == "" {
+			return nil, errors.New("missing -grantee")
+		}
+		grantee, err := resolveAddress(kr, p.grantee, net.Bech32)
+		if err != nil {
+			return nil, err
+		}
+		// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/feegrant/spec/03_messages.md#msgrevokeallowance
+		msg := feegrant.NewMsgRevokeAllowance(from.address, grantee)
+		return []sdk.Msg{&msg}, nil
+	})
+}
+
+// Parse the flags, load the signer, build the messages with newMsgs and send them in a transaction
+func runFeegrantTx(name string, args []string, newMsgs func(p feegrantParams, net network, kr *keyring.Keyring, from account) ([]sdk.Msg, error)) error {
+	var p feegrantParams
+	flags := newFeegrantFlagSet(name, &p)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	msgs, err := newMsgs(p, net, kr, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := sendAndWait(c, net, from, p.dryRun, p.wait, msgs...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// The basic allowance, periodic when -period is set, limited to -allowed-msgs when it is not empty
+// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/feegrant/spec/01_concepts.md#fee-allowances
+func newAllowance(p feegrantParams, now time.Time) (feegrant.FeeAllowanceI, error) {
+	spendLimit, err := sdk.ParseCoinsNormalized(p.spendLimit)
+	if err != nil {
+		return nil, fmt.Errorf("-spend-limit: %w", err)
+	}
+	basic := feegrant.BasicAllowance{SpendLimit: spendLimit}
+	if p.expiration > 0 {
+		expiration := now.Add(p.expiration)
+		basic.Expiration = &expiration
+	}
+
+	var allowance feegrant.FeeAllowanceI = &basic
+	if p.period > 0 {
+		periodLimit, err := sdk.ParseCoinsNormalized(p.periodLimit)
+		if err != nil {
+			return nil, fmt.Errorf("-period-limit: %w", err)
+		}
+		if periodLimit.Empty() {
+			return nil, errors.New("a periodic allowance needs -period-limit")
+		}
+		// the first period starts now with the whole period limit available
+		allowance = &feegrant.PeriodicAllowance{
+			Basic:            basic,
+			Period:           p.period,
+			PeriodSpendLimit: periodLimit,
+			PeriodCanSpend:   periodLimit,
+			PeriodReset:      now.Add(p.period),
+		}
+	} else if p.periodLimit != "" {
+		return nil, errors.New("-period-limit needs -period")
+	}
+
+	var msgs []string
+	for _, msg := range strings.Split(p.allowedMsgs, ",") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	if len(msgs) == 0 {
+		return allowance, nil
+	}
+	// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/feegrant#NewAllowedMsgAllowance
+	return feegrant.NewAllowedMsgAllowance(allowance, msgs)
+}
+
+// feegrant allowances [-granter <key or address>] [-grantee <key or address>] [-limit n] [-offset n] [-reverse] [flags]
+func runFeegrantAllowances(args []string) error {
+	var p feegrantParams
+	flags := newFeegrantFlagSet("feegrant allowances", &p)
+	page := newPageFlags(flags)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if p.granter == "" && p.grantee == "" {
+		return errors.New("feegrant allowances: missing -granter or -grantee")
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	granter, grantee, err := resolveGrantPair(kr, p.granter, p.grantee, net.Bech32)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var grants []*feegrant.Grant
+	switch {
+	case granter != nil && grantee != nil:
+		grant, err := c.Allowance(ctx, granter, grantee)
+		if err != nil {
+			return err
+		}
+		grants = append(grants, grant)
+	case granter != nil:
+		if grants, err = c.GranterAllowances(ctx, granter, page.options()...); err != nil {
+			return err
+		}
+	default:
+		if grants, err = c.GranteeAllowances(ctx, grantee, page.options()...); err != nil {
+			return err
+		}
+	}
+	if len(grants) == 0 {
+		fmt.Println("No allowances")
+	}
+	for _, grant := range grants {
+		allowance, err := c.UnpackAllowance(grant.Allowance)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\t%s\n", grant.Granter, grant.Grantee, client.DescribeAllowance(allowance))
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const govUsage = `usage: submit-transaction gov <command> [flags]
+
+queries:
+  proposals  list the proposals, filtered by -status(deposit, voting, passed, rejected or failed), -voter or -depositor
+  proposal   details of the proposal -id, its tally and the vote of -voter when it is set
+  tally      tally result of the proposal -id, the current one during the voting period
+
+transactions, signed by the -from key:
+  vote           vote -option(yes, no, no_with_veto or abstain) on the proposal -id
+  weighted-vote  split the voting power between -options, ie: yes=0.6,no=0.4, the weights must add up to 1
+  deposit        deposit -amount in the network denom on the proposal -id`
+
+var govCommands = map[string]func(args []string) error{
+	"proposals":     runGovProposals,
+	"proposal":      runGovProposal,
+	"tally":         runGovTally,
+	"vote":          runGovVote,
+	"weighted-vote": runGovWeightedVote,
+	"deposit":       runGovDeposit,
+}
+
+// gov <command> [flags]
+func runGov(args []string) error {
+	if len(args) == 0 {
+		return errors.New(govUsage)
+	}
+	command, ok := govCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("gov: unknown command %q\n%s", args[0], govUsage)
+	}
+	return command(args[1:])
+}
+
+// Flags of the gov commands
+type govParams struct {
+	params
+	id        uint64
+	status    string
+	voter     string
+	depositor string
+	option    string
+	options   string
+	wait      bool
+}
+
+func newGovFlagSet(name string, p *govParams) *flag.FlagSet {
+	flags := newFlagSet(name, &p.params)
+	flags.Uint64Var(&p.id, "id", 0, "proposal id")
+	flags.StringVar(&p.status, "status", "", "proposal status: deposit, voting, passed, rejected or failed, all when empty")
+	flags.StringVar(&p.voter, "voter", "", "voter, name of a key in the keyring or bech32 address, filters the proposals or shows its vote on a proposal")
+	flags.StringVar(&p.depositor, "depositor", "", "proposals with deposits of, name of a key in the keyring or bech32 address")
+	flags.StringVar(&p.option, "option", "", "vote option: yes, no, no_with_veto or abstain")
+	flags.StringVar(&p.options, "options", "", "weighted vote options, ie: yes=0.6,no=0.4")
+	flags.BoolVar(&p.wait, "wait", false, "wait until the transaction is included in a block")
+	return flags
+}
+
+// gov vote -id n -option <option> [flags]
+func runGovVote(args []string) error {
+	return runGovTx("gov vote", args, func(p govParams, net network, from account) ([]sdk.Msg, error) {
+		option, err := parseVoteOption(p.option)
+		if err != nil {
+			return nil, fmt.Errorf("-option: %w", err)
+		}
+		fmt.Println("Vote", option, "on proposal", p.id)
+		// https://docs.cosmos.network/v0.46/modules/gov/03_messages.html#vote
+		return []sdk.Msg{govv1beta1.NewMsgVote(from.address, p.id, option)}, nil
+	})
+}
+
+// gov weighted-vote -id n -options <option=weight,...> [flags]
+func runGovWeightedVote(args []string) error {
+	return runGovTx("gov weighted-vote", args, func(p govParams, net network, from account) ([]sdk.Msg, error) {
+		var options govv1beta1.WeightedVoteOptions
+		for _, option := range strings.Split(p.options, ",") {
+			fields := strings.Split(strings.TrimSpace(option), "=")
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("-options: %q is not option=weight", option)
+			}
+			voteOption, err := parseVoteOption(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("-options: %w", err)
+			}
+			weight, err := sdk.NewDecFromStr(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("-options: weight of %s: %w", fields[0], err)
+			}
+			options = append(options, govv1beta1.WeightedVoteOption{Option: voteOption, Weight: weight})
+		}
+		msg := govv1beta1.NewMsgVoteWeighted(from.address, p.id, options)
+		// checks the weights add up to 1 and the options are not repeated
+		if err := msg.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		fmt.Println("Vote", options, "on proposal", p.id)
+		return []sdk.Msg{msg}, nil
+	})
+}
+
+// gov deposit -id n -amount n [flags]
+func runGovDeposit(args []string) error {
+	return runGovTx("gov deposit", args, func(p govParams, net network, from account) ([]sdk.Msg, error) {
+		amount, err := parseAmount(net, p.amount)
+		if err != nil {
+			return nil, fmt.Errorf("-amount: %w", err)
+		}
+		fmt.Println("Deposit", amount.String(), "on proposal", p.id)
+		// https://docs.cosmos.network/v0.46/modules/gov/03_messages.html#deposit
+		return []sdk.Msg{govv1beta1.NewMsgDeposit(from.address, p.id, sdk.NewCoins(amount))}, nil
+	})
+}
+
+// Parse the flags, load the signer, build the messages with newMsgs and send them in a transaction
+func runGovTx(name string, args []string, newMsgs func(p govParams, net network, from account) ([]sdk.Msg, error)) error {
+	var p govParams
+	flags := newGovFlagSet(name, &p)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if p.id == 0 {
+		return fmt.Errorf("%s: missing -id", name)
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	msgs, err := newMsgs(p, net, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := sendAndWait(c, net, from, p.dryRun, p.wait, msgs...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// The vote option of yes, no, no_with_veto or abstain, the enum names(ie: VOTE_OPTION_YES) are accepted too
+func parseVoteOption(option string) (govv1beta1.VoteOption, error) {
+	option = strings.ToUpper(strings.TrimSpace(option))
+	if !strings.HasPrefix(option, "VOTE_OPTION_") {
+		option = "VOTE_OPTION_" + option
+	}
+	voteOption, err := govv1beta1.VoteOptionFromString(option)
+	if err != nil {
+		return voteOption, err
+	}
+	if !govv1beta1.ValidVoteOption(voteOption) {
+		return voteOption, fmt.Errorf("%s is not a vote option", option)
+	}
+	return voteOption, nil
+}
+
+// The proposal status of deposit, voting, passed, rejected or failed, StatusNil(any status) when it is empty
+func parseProposalStatus(status string) (govv1beta1.ProposalStatus, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status {
+	case "":
+		return govv1beta1.StatusNil, nil
+	case "DEPOSIT", "VOTING":
+		status += "_PERIOD"
+	}
+	if !strings.HasPrefix(status, "PROPOSAL_STATUS_") {
+		status = "PROPOSAL_STATUS_" + status
+	}
+	return govv1beta1.ProposalStatusFromString(status)
+}
+
+// gov proposals [-status <status>] [-voter <key or address>] [-depositor <key or address>] [-limit n] [-offset n] [-reverse] [flags]
+func runGovProposals(args []string) error {
+	return runGovQuery("gov proposals", args, func(p govParams, net network, kr *keyring.Keyring, c *client.Client, page []client.PageOption) error {
+		status, err := parseProposalStatus(p.status)
+		if err != nil {
+			return fmt.Errorf("-status: %w", err)
+		}
+		var voter, depositor sdk.AccAddress
+		if p.voter != "" {
+			if voter, err = resolveAddress(kr, p.voter, net.Bech32); err != nil {
+				return err
+			}
+		}
+		if p.depositor != "" {
+			if depositor, err = resolveAddress(kr, p.depositor, net.Bech32); err != nil {
+				return err
+			}
+		}
+		proposals, err := c.Proposals(context.Background(), status, voter, depositor, page...)
+		if err != nil {
+			return err
+		}
+		if len(proposals) == 0 {
+			fmt.Println("No proposals")
+		}
+		for _, proposal := range proposals {
+			fmt.Printf("%d\t%s\tvoting end %s\t%s\n", proposal.ProposalId, proposal.Status, proposal.VotingEndTime.Format("2006-01-02 15:04:05 MST"), c.ProposalTitle(proposal))
+		}
+		return nil
+	})
+}
+
+// gov proposal -id n [-voter <key or address>] [flags]
+func runGovProposal(args []string) error {
+	return runGovQuery("gov proposal", args, func(p govParams, net network, kr *keyring.Keyring, c *client.Client, page []client.PageOption) error {
+		if p.id == 0 {
+			return errors.New("missing -id")
+		}
+		ctx := context.Background()
+		proposal, err := c.Proposal(ctx, p.id)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Proposal", proposal.ProposalId, c.ProposalTitle(*proposal))
+		fmt.Println(" Type", proposal.Content.GetTypeUrl())
+		fmt.Println(" Status", proposal.Status)
+		fmt.Println(" Submitted", proposal.SubmitTime.Format("2006-01-02 15:04:05 MST"))
+		fmt.Println(" Deposit", proposal.TotalDeposit.String(), "until", proposal.DepositEndTime.Format("2006-01-02 15:04:05 MST"))
+		fmt.Println(" Voting", proposal.VotingStartTime.Format("2006-01-02 15:04:05 MST"), "-", proposal.VotingEndTime.Format("2006-01-02 15:04:05 MST"))
+		if content := proposal.GetContent(); content != nil {
+			fmt.Println(" Description", content.GetDescription())
+		}
+
+		// the final tally is stored when the voting period ends, before it is computed by the query
+		tally := &proposal.FinalTallyResult
+		if proposal.Status == govv1beta1.StatusVotingPeriod {
+			if tally, err = c.TallyResult(ctx, p.id); err != nil {
+				return err
+			}
+		}
+		printTally(*tally)
+
+		if p.voter != "" {
+			voter, err := resolveAddress(kr, p.voter, net.Bech32)
+			if err != nil {
+				return err
+			}
+			vote, err := c.Vote(ctx, p.id, voter)
+			if isVoteNotFound(err) {
+				fmt.Println(" Vote of", net.Bech32.AccAddress(voter), "none")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Println(" Vote of", net.Bech32.AccAddress(voter), vote.Options)
+		}
+		return nil
+	})
+}
+
+// The voter has not voted. The sdk v0.46 returns InvalidArgument "voter: ... not found for proposal: ..." and the
+// later versions NotFound, any other error is a failed query.
+// https://github.com/cosmos/cosmos-sdk/blob/v0.46.1/x/gov/keeper/grpc_query.go#L114
+func isVoteNotFound(err error) bool {
+	st := status.Convert(errors.Unwrap(err))
+	switch st.Code() {
+	case codes.NotFound:
+		return true
+	case codes.InvalidArgument:
+		return strings.Contains(st.Message(), "not found for proposal")
+	}
+	return false
+}
+
+// gov tally -id n [flags]
+func runGovTally(args []string) error {
+	return runGovQuery("gov tally", args, func(p govParams, net network, kr *keyring.Keyring, c *client.Client, page []client.PageOption) error {
+		if p.id == 0 {
+			return errors.New("missing -id")
+		}
+		tally, err := c.TallyResult(context.Background(), p.id)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Proposal", p.id)
+		printTally(*tally)
+		return nil
+	})
+}
+
+// Print the votes of each option and their share of the voting power that voted
+func printTally(tally govv1beta1.TallyResult) {
+	total := tally.Yes.Add(tally.No).Add(tally.NoWithVeto).Add(tally.Abstain)
+	for _, option := range []struct {
+		name  string
+		votes sdk.Int
+	}{{"yes", tally.Yes}, {"no", tally.No}, {"no_with_veto", tally.NoWithVeto}, {"abstain", tally.Abstain}} {
+		share := sdk.ZeroDec()
+		if total.IsPositive() {
+			share = sdk.NewDecFromInt(option.votes).QuoInt(total).MulInt64(100)
+		}
+		fmt.Printf(" %s\t%s\t%.2f%%\n", option.name, option.votes.String(), share.MustFloat64())
+	}
+}
+
+// Parse the flags and run query with the keyring to resolve the addresses, the page flags limit the lists
+func runGovQuery(name string, args []string, query func(p govParams, net network, kr *keyring.Keyring, c *client.Client, page []client.PageOption) error) error {
+	var p govParams
+	flags := newGovFlagSet(name, &p)
+	page := newPageFlags(flags)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := query(p, net, kr, c, page.options()); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
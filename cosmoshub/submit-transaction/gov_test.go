@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsVoteNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		notFound bool
+	}{
+		{"v0.46 not voted", status.Error(codes.InvalidArgument, "voter: cosmos1abc not found for proposal: 5"), true},
+		{"not found", status.Error(codes.NotFound, "vote not found"), true},
+		{"invalid address", status.Error(codes.InvalidArgument, "decoding bech32 failed"), false},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), false},
+		{"not a status", errors.New("not found for proposal"), false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.err != nil {
+				err = fmt.Errorf("query vote 5 of cosmos1abc: %w", tt.err)
+			}
+
+			sut := isVoteNotFound(err)
+
+			if sut != tt.notFound {
+				t.Errorf("isVoteNotFound(%v) should be %v but is %v", err, tt.notFound, sut)
+			}
+		})
+	}
+}
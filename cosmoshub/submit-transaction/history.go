@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+const historyUsage = `usage: submit-transaction history -from <key or address> [flags]
+
+searches the transactions sent by the address(message.sender) and the transfers it received(transfer.recipient),
+the node must index them. Every transfer of the address is a ledger entry:
+  direction     out, in, self or fee when the transaction has no transfers of the address(ie: a vote)
+  counterparty  the receiver of an out transfer or the sender of an in transfer
+  fee           paid by the address, only in the first entry of each transaction`
+
+// pages of the event queries, the nodes cap the limit(100 by default)
+const defaultHistoryPageSize = 50
+
+// A normalized movement of the address in a transaction
+type ledgerEntry struct {
+	Height       int64  `json:"height"`
+	Time         string `json:"time"`
+	Hash         string `json:"hash"`
+	Direction    string `json:"direction"`
+	Counterparty string `json:"counterparty"`
+	Amount       string `json:"amount"`
+	Fee          string `json:"fee"`
+	Code         uint32 `json:"code"` // not 0 when the transaction failed, only the fee is paid
+}
+
+// A transaction found by the event queries
+type historyTx struct {
+	res *sdk.TxResponse
+	tx  *typestx.Tx
+}
+
+// history -from <key or address> [-direction all|in|out] [-max n] [-format table|csv|json] [-out file] [flags]
+func runHistory(args []string) error {
+	var p params
+	flags := newFlagSet("history", &p)
+	direction := flags.String("direction", "all", "transactions to search: all, in(transfer.recipient) or out(message.sender)")
+	order := flags.String("order", "asc", "order by height: asc or desc")
+	pageSize := flags.Uint64("page-size", defaultHistoryPageSize, "transactions requested in each page")
+	max := flags.Uint64("max", 0, "max transactions of each query, 0 is all of them")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	out := flags.String("out", "", "file where the ledger is written, stdout when empty")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *pageSize == 0 {
+		return errors.New("history: -page-size must be positive")
+	}
+	var queries []string
+	switch *direction {
+	case "all":
+		queries = []string{"message.sender", "transfer.recipient"}
+	case "out":
+		queries = []string{"message.sender"}
+	case "in":
+		queries = []string{"transfer.recipient"}
+	default:
+		return fmt.Errorf("history: unknown -direction %q\n%s", *direction, historyUsage)
+	}
+	orderBy := typestx.OrderBy_ORDER_BY_ASC
+	if *order == "desc" {
+		orderBy = typestx.OrderBy_ORDER_BY_DESC
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	address, err := resolveAddress(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// the transactions of both queries, a send to itself is found by both
+	seen := map[string]bool{}
+	var txs []historyTx
+	for _, key := range queries {
+		// doc for query syntax https://pkg.go.dev/github.com/tendermint/tendermint/libs/pubsub/query
+		event := fmt.Sprintf("%s='%s'", key, net.Bech32.AccAddress(address))
+		found, err := searchAllTxs(c, event, orderBy, *pageSize, *max)
+		if err != nil {
+			return fmt.Errorf("history: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "Found", len(found), "transactions with", event)
+		for _, tx := range found {
+			if !seen[tx.res.TxHash] {
+				seen[tx.res.TxHash] = true
+				txs = append(txs, tx)
+			}
+		}
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		if orderBy == typestx.OrderBy_ORDER_BY_DESC {
+			return txs[i].res.Height > txs[j].res.Height
+		}
+		return txs[i].res.Height < txs[j].res.Height
+	})
+
+	var entries []ledgerEntry
+	for _, tx := range txs {
+		feePayer, err := c.FeePayer(tx.tx)
+		if err != nil {
+			return fmt.Errorf("history: fee payer of %s: %w", tx.res.TxHash, err)
+		}
+		entries = append(entries, ledgerEntries(net.Bech32.AccAddress(address), tx.res, tx.tx, net.Bech32.AccAddress(feePayer))...)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeLedger(w, entries, *format); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintln(os.Stderr, len(entries), "ledger entries written to", *out)
+	}
+	return nil
+}
+
+// Request the pages of the event query until all the transactions, or max when it is not 0, are found
+func searchAllTxs(c *client.Client, event string, orderBy typestx.OrderBy, pageSize uint64, max uint64) ([]historyTx, error) {
+	var txs []historyTx
+	for page := uint64(1); ; page++ {
+		res, err := c.SearchTxs(context.Background(), []string{event}, page, pageSize, orderBy)
+		if err != nil {
+			return nil, err
+		}
+		for i, txRes := range res.Responses {
+			if max > 0 && uint64(len(txs)) == max {
+				return txs, nil
+			}
+			txs = append(txs, historyTx{res: txRes, tx: res.Txs[i]})
+		}
+		if len(res.Responses) == 0 || uint64(len(txs)) >= res.Total {
+			return txs, nil
+		}
+	}
+}
+
+// The ledger entries of address in a transaction: one per transfer event of the messages where it is the sender or
+// the recipient. The fee is in the first entry when address paid it, a failed transaction only has the fee entry.
+func ledgerEntries(address string, res *sdk.TxResponse, tx *typestx.Tx, feePayer string) []ledgerEntry {
+	entry := ledgerEntry{Height: res.Height, Time: res.Timestamp, Hash: res.TxHash, Code: res.Code}
+	var entries []ledgerEntry
+	for _, log := range res.Logs {
+		for _, event := range log.Events {
+			if event.Type != "transfer" {
+				continue
+			}
+			for _, transfer := range parseTransfers(event) {
+				e := entry
+				e.Amount = transfer.amount
+				switch {
+				case transfer.sender == address && transfer.recipient == address:
+					e.Direction, e.Counterparty = "self", address
+				case transfer.sender == address:
+					e.Direction, e.Counterparty = "out", transfer.recipient
+				case transfer.recipient == address:
+					e.Direction, e.Counterparty = "in", transfer.sender
+				default:
+					continue
+				}
+				entries = append(entries, e)
+			}
+		}
+	}
+	if feePayer != address {
+		return entries
+	}
+	fee := tx.GetAuthInfo().GetFee().GetAmount().String()
+	if len(entries) == 0 {
+		entry.Direction, entry.Fee = "fee", fee
+		return []ledgerEntry{entry}
+	}
+	entries[0].Fee = fee
+	return entries
+}
+
+type transfer struct {
+	sender    string
+	recipient string
+	amount    string
+}
+
+// The transfers of a transfer event, the attributes of all the transfers of a message are in the same event:
+// recipient, sender, amount, recipient, sender, amount...
+func parseTransfers(event sdk.StringEvent) []transfer {
+	var transfers []transfer
+	current := map[string]string{}
+	flush := func() {
+		if len(current) > 0 {
+			transfers = append(transfers, transfer{sender: current["sender"], recipient: current["recipient"], amount: current["amount"]})
+			current = map[string]string{}
+		}
+	}
+	for _, attribute := range event.Attributes {
+		if _, ok := current[attribute.Key]; ok {
+			flush()
+		}
+		current[attribute.Key] = attribute.Value
+	}
+	flush()
+	return transfers
+}
+
+// Write the entries as a table, CSV with a header or a JSON array
+func writeLedger(w io.Writer, entries []ledgerEntry, format string) error {
+	switch format {
+	case "table":
+		if len(entries) == 0 {
+			fmt.Fprintln(w, "No transactions")
+		}
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\tfee %s\tcode %d\n", e.Height, e.Time, e.Hash, e.Direction, e.Counterparty, e.Amount, e.Fee, e.Code)
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w) // https://pkg.go.dev/encoding/csv#Writer
+		cw.Write([]string{"height", "time", "hash", "direction", "counterparty", "amount", "fee", "code"})
+		for _, e := range entries {
+			cw.Write([]string{strconv.FormatInt(e.Height, 10), e.Time, e.Hash, e.Direction, e.Counterparty, e.Amount, e.Fee, strconv.FormatUint(uint64(e.Code), 10)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		if entries == nil {
+			entries = []ledgerEntry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	default:
+		return fmt.Errorf("unknown -format %q", format)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	typestx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+func transferEvent(attributes ...string) sdk.StringEvent {
+	event := sdk.StringEvent{Type: "transfer"}
+	for i := 0; i < len(attributes); i += 2 {
+		event.Attributes = append(event.Attributes, sdk.Attribute{Key: attributes[i], Value: attributes[i+1]})
+	}
+	return event
+}
+
+func feeTx(amount int64) *typestx.Tx {
+	return &typestx.Tx{AuthInfo: &typestx.AuthInfo{Fee: &typestx.Fee{Amount: sdk.NewCoins(sdk.NewInt64Coin("uatom", amount))}}}
+}
+
+func TestLedgerEntries(t *testing.T) {
+	res := &sdk.TxResponse{Height: 10, TxHash: "HASH", Timestamp: "2022-10-01T00:00:00Z", Logs: sdk.ABCIMessageLogs{{
+		Events: sdk.StringEvents{
+			{Type: "message", Attributes: []sdk.Attribute{{Key: "sender", Value: "me"}}},
+			transferEvent("recipient", "alice", "sender", "me", "amount", "100uatom", "recipient", "me", "sender", "bob", "amount", "50uatom", "recipient", "carol", "sender", "bob", "amount", "1uatom"),
+		},
+	}}}
+
+	sut := ledgerEntries("me", res, feeTx(500), "me")
+
+	if len(sut) != 2 {
+		t.Fatalf("ledger should have the out and in transfers of me but has %v", sut)
+	}
+	if sut[0].Direction != "out" || sut[0].Counterparty != "alice" || sut[0].Amount != "100uatom" || sut[0].Fee != "500uatom" {
+		t.Errorf("first entry should be out 100uatom to alice with the fee but is %+v", sut[0])
+	}
+	if sut[1].Direction != "in" || sut[1].Counterparty != "bob" || sut[1].Amount != "50uatom" || sut[1].Fee != "" {
+		t.Errorf("second entry should be in 50uatom from bob without fee but is %+v", sut[1])
+	}
+	if sut[1].Height != 10 || sut[1].Hash != "HASH" || sut[1].Time != "2022-10-01T00:00:00Z" {
+		t.Errorf("entries should have the height, hash and time of the transaction but is %+v", sut[1])
+	}
+}
+
+func TestLedgerEntriesFeeOnly(t *testing.T) {
+	failed := &sdk.TxResponse{Height: 11, TxHash: "FAILED", Code: 5}
+
+	sut := ledgerEntries("me", failed, feeTx(500), "me")
+
+	if len(sut) != 1 || sut[0].Direction != "fee" || sut[0].Fee != "500uatom" || sut[0].Code != 5 {
+		t.Errorf("a failed transaction paid by me should have a fee entry but has %+v", sut)
+	}
+	if others := ledgerEntries("me", failed, feeTx(500), "granter"); len(others) != 0 {
+		t.Errorf("a fee paid by the granter should not be in the ledger of me but has %+v", others)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	"golang.org/x/term"
+)
+
+// environment variable with the keyring passphrase, when it is not set the passphrase is prompted
+const passphraseEnv = "KEYRING_PASSPHRASE"
+
+const keysUsage = `usage: submit-transaction keys <command> [flags] <name>
+
+commands:
+  add      generate a new mnemonic and store it encrypted
+  import   import an existing mnemonic, -mnemonic or prompted. Several keys can share a mnemonic using different paths
+  list     list the stored keys
+  show     show the address and public key of a key
+  export   write the mnemonic of a key to the -out file
+  delete   delete a key`
+
+// keys <command> [flags] <name>
+func runKeys(args []string) error {
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+	command := args[0]
+	flags := flag.NewFlagSet("keys "+command, flag.ContinueOnError)
+	dir := flags.String("keyring-dir", "keyring", "directory where the encrypted keys are stored")
+	mnemonic := flags.String("mnemonic", "", "mnemonic to import, prompted when empty")
+	out := flags.String("out", "", "file where the exported mnemonic is written")
+	path := pathFlags(flags)
+	withBIP39Passphrase := flags.Bool("bip39-passphrase", false, "prompt for the optional BIP39 passphrase")
+	algo := flags.String("algo", keyring.AlgoSecp256k1, "address algorithm of add and import: secp256k1 or eth_secp256k1(ie: coin type 60)")
+	// the addresses are printed with the bech32 prefix of the network profile
+	var p params
+	flags.StringVar(&p.configFile, "config", "config.yaml", "path to the YAML config file with the network profiles")
+	flags.StringVar(&p.network, "network", "", "network profile whose bech32 prefix encodes the addresses")
+	flags.StringVar(&p.overrides.Bech32.Account, "bech32-prefix", "", "bech32 account prefix(ie: osmo), overrides the profile")
+	if err := parseArgs(flags, args[1:]); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(*dir)
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(0)
+	if command != "list" && name == "" {
+		return fmt.Errorf("keys %s: missing key name\n%s", command, keysUsage)
+	}
+
+	opts := keyring.Options{Path: path.path(), Algo: *algo}
+	if *withBIP39Passphrase && (command == "add" || command == "import") {
+		if opts.BIP39Passphrase, err = readSecret("BIP39 passphrase: "); err != nil {
+			return err
+		}
+	}
+
+	switch command {
+	case "add":
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			return err
+		}
+		info, err := kr.Add(name, passphrase, opts)
+		if err != nil {
+			return err
+		}
+		printKey(info, net.Bech32)
+		fmt.Println("Backup the mnemonic with: submit-transaction keys export -out <file>", name)
+	case "import":
+		if *mnemonic == "" {
+			if *mnemonic, err = readSecret("Mnemonic: "); err != nil {
+				return err
+			}
+		}
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			return err
+		}
+		info, err := kr.Import(name, *mnemonic, passphrase, opts)
+		if err != nil {
+			return err
+		}
+		printKey(info, net.Bech32)
+	case "list":
+		infos, err := kr.List()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			printKey(info, net.Bech32)
+		}
+	case "show":
+		info, err := kr.Show(name)
+		if err != nil {
+			return err
+		}
+		printKey(info, net.Bech32)
+	case "export":
+		// the mnemonic is never printed, it is written to a file only readable by the user
+		if *out == "" {
+			return errors.New("keys export: missing -out file")
+		}
+		passphrase, err := readPassphrase(false)
+		if err != nil {
+			return err
+		}
+		words, err := kr.ExportMnemonic(name, passphrase)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out, []byte(words+"\n"), 0o600); err != nil {
+			return err
+		}
+		fmt.Println("Mnemonic of", name, "written to", *out)
+	case "delete":
+		if err := kr.Delete(name); err != nil {
+			return err
+		}
+		fmt.Println("Deleted", name)
+	default:
+		return fmt.Errorf("keys: unknown command %q\n%s", command, keysUsage)
+	}
+	return nil
+}
+
+func printKey(info keyring.Info, prefixes client.Bech32Prefixes) {
+	fmt.Printf("%s\t%s\t%s\t%s\t%X\n", info.Name, prefixes.AccAddress(info.Address), info.Path, info.Algo, info.PubKey.Bytes())
+}
+
+// Read the keyring passphrase from the environment or prompt it, confirm asks twice for new keys
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase, ok := os.LookupEnv(passphraseEnv); ok {
+		return passphrase, nil
+	}
+	passphrase, err := readSecret("Keyring passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		repeated, err := readSecret("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// shared reader, several secrets can be piped through stdin one per line
+var stdin = bufio.NewReader(os.Stdin)
+
+// Read a line from the terminal without echo, when stdin is not a terminal it is read as a line
+// https://pkg.go.dev/golang.org/x/term#ReadPassword
+func readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := stdin.ReadString('\n')
+		fmt.Fprintln(os.Stderr)
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimSpace(line), err
+	}
+	body, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return strings.TrimSpace(string(body)), err
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunKeysPrefix(t *testing.T) {
+	t.Setenv(passphraseEnv, "passphrase")
+	dir := t.TempDir()
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	flags := []string{"-keyring-dir", dir, "-config", filepath.Join(dir, "missing.yaml")}
+	if err := runKeys(append(append([]string{"import"}, flags...), "-mnemonic", mnemonic, "alice")); err != nil {
+		t.Fatalf("keys import error %v", err)
+	}
+
+	sut := captureStdout(t, func() {
+		if err := runKeys(append(append([]string{"show"}, flags...), "-bech32-prefix", "osmo", "alice")); err != nil {
+			t.Errorf("keys show error %v", err)
+		}
+	})
+
+	if !strings.HasPrefix(sut, "alice\tosmo19rl4cm2hmr8afy4kldpxz3fka4jguq0a5m7df8\t") {
+		t.Errorf("keys show should print the address with the osmo prefix but prints %q", sut)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/fakenode"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+var testNetwork = network{
+	ChainID:       "test-chain",
+	Denom:         "uatom",
+	GasPrice:      "0.025uatom",
+	GasAdjustment: 1.3,
+	Bech32:        client.CosmosPrefixes,
+	TxTimeout:     time.Second,
+}
+
+// an in-memory node and a client connected to it, both are closed at the end of the test
+func startNode(t *testing.T) (*fakenode.Node, *client.Client) {
+	node := fakenode.Start(testNetwork.ChainID)
+	t.Cleanup(node.Close)
+	c, err := node.Dial(client.WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Dial error %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return node, c
+}
+
+func newTestAccount() account {
+	privKey := secp256k1.GenPrivKey()
+	return account{privKey: privKey, pubKey: privKey.PubKey(), address: sdk.AccAddress(privKey.PubKey().Address()), prefixes: client.CosmosPrefixes}
+}
+
+// the output printed to stdout while f runs
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe error %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read stdout error %v", err)
+	}
+	return string(out)
+}
+
+func TestGetAccount(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.AddAccount(sdk.AccAddress([]byte("other_______________")))
+	number := node.AddAccount(from.address)
+	node.SetSequence(from.address, 4)
+
+	sut, err := getAccount(c, from.address)
+
+	if err != nil {
+		t.Fatalf("getAccount error %v", err)
+	}
+	if sut.GetAccountNumber() != number || sut.GetSequence() != 4 {
+		t.Errorf("getAccount should return the account number %d and sequence 4 but returns %d and %d", number, sut.GetAccountNumber(), sut.GetSequence())
+	}
+}
+
+func TestGetAccountNotFound(t *testing.T) {
+	_, c := startNode(t)
+
+	_, err := getAccount(c, newTestAccount().address)
+
+	if !errors.Is(err, client.ErrAccountNotFound) || exitCode(err) != client.ExitAccountNotFound {
+		t.Errorf("getAccount of an address without transfers should be ErrAccountNotFound but is %v", err)
+	}
+}
+
+func TestVerifyBalance(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.SetBalance(from.address, sdk.NewInt64Coin("uatom", 1500000))
+	node.SetDenomsMetadata(banktypes.Metadata{
+		Base:       "uatom",
+		Display:    "atom",
+		DenomUnits: []*banktypes.DenomUnit{{Denom: "uatom"}, {Denom: "atom", Exponent: 6}},
+	})
+
+	sut := captureStdout(t, func() {
+		if err := verifyBalance(c, loadDenoms(c), from.address, "uatom", "from before"); err != nil {
+			t.Errorf("verifyBalance error %v", err)
+		}
+	})
+
+	expected := "from before balance " + from.bech32() + " 1.5atom(1500000uatom)\n"
+	if sut != expected {
+		t.Errorf("verifyBalance should print %q but prints %q", expected, sut)
+	}
+}
+
+func TestResolveGasPrice(t *testing.T) {
+	sut, err := resolveGasPrice(testNetwork)
+
+	if err != nil || sut.String() != "0.025000000000000000uatom" {
+		t.Errorf("the gas price should be the profile one but is %v, %v", sut, err)
+	}
+	if _, err := resolveGasPrice(network{Denom: "uatom"}); err == nil {
+		t.Errorf("a profile without gas price should fail")
+	}
+}
+
+func TestSendAndWaitForTransaction(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	to := sdk.AccAddress([]byte("to__________________"))
+	number := node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	node.SetInclusionDelay(2)
+
+	txRes, err := sendMessages(c, testNetwork, from, number, 0, "", false, newMsgSend(from.address, to, sdk.NewInt64Coin("uatom", 10000)))
+	if err != nil || txRes.Code != 0 {
+		t.Fatalf("sendMessages should broadcast the transaction but returns %v %v", txRes, err)
+	}
+	sut, err := waitForTransaction(c, testNetwork, txRes)
+
+	if err != nil {
+		t.Fatalf("waitForTransaction error %v", err)
+	}
+	if sut.Hash != txRes.TxHash || sut.Height != 2 || sut.GasUsed != fakenode.DefaultGasUsed {
+		t.Errorf("the transaction should be included at height 2 but is %+v", sut)
+	}
+	// the fee is the adjusted simulated gas 104000 * 0.025uatom
+	if balance := node.Balance(from.address); balance.String() != "987400uatom" {
+		t.Errorf("from should pay the amount and a 2600uatom fee but has %s", balance)
+	}
+	if balance := node.Balance(to); balance.String() != "10000uatom" {
+		t.Errorf("to should receive 10000uatom but has %s", balance)
+	}
+	if node.Sequence(from.address) != 1 {
+		t.Errorf("the sequence of from should be incremented but is %d", node.Sequence(from.address))
+	}
+}
+
+func TestBroadcastTransactionSequenceMismatch(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	number := node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	node.SetSequence(from.address, 2)
+
+	sut, err := sendMessages(c, testNetwork, from, number, 5, "", false, newMsgSend(from.address, from.address, sdk.NewInt64Coin("uatom", 1)))
+
+	if err != nil {
+		t.Fatalf("sendMessages error %v", err)
+	}
+	if expected, ok := isSequenceMismatch(sut, nil); !ok || expected != 2 {
+		t.Errorf("the transaction should be rejected expecting the sequence 2 but is %d %s", sut.Code, sut.RawLog)
+	}
+	if len(node.Txs()) != 0 {
+		t.Errorf("a rejected transaction should not be accepted but %d are", len(node.Txs()))
+	}
+}
+
+func TestBroadcastTransactionWrongChain(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	number := node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	net := testNetwork
+	net.ChainID = "other-chain"
+
+	sut, err := sendMessages(c, net, from, number, 0, "", false, newMsgSend(from.address, from.address, sdk.NewInt64Coin("uatom", 1)))
+
+	if err != nil {
+		t.Fatalf("sendMessages error %v", err)
+	}
+	if sut.Code != 4 || !strings.Contains(sut.RawLog, "signature verification failed") {
+		t.Errorf("a transaction signed for another chain should be unauthorized but is %d %s", sut.Code, sut.RawLog)
+	}
+}
+
+func TestWaitForTransactionFailed(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	to := sdk.AccAddress([]byte("to__________________"))
+	number := node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	node.FailNextTx(5, "sdk", "insufficient funds")
+
+	txRes, err := sendMessages(c, testNetwork, from, number, 0, "", false, newMsgSend(from.address, to, sdk.NewInt64Coin("uatom", 10000)))
+	if err != nil || txRes.Code != 0 {
+		t.Fatalf("sendMessages should broadcast the transaction but returns %v %v", txRes, err)
+	}
+	sut, err := waitForTransaction(c, testNetwork, txRes)
+
+	if sut == nil || !sut.Failed() || sut.Code != 5 {
+		t.Errorf("waitForTransaction should return the failed delivery but returns %+v", sut)
+	}
+	var txErr *client.TxError
+	if !errors.As(err, &txErr) || txErr.Height == 0 || !errors.Is(err, client.ErrInsufficientFunds) || exitCode(err) != client.ExitInsufficientFunds {
+		t.Errorf("the error should be a failed delivery of insufficient funds but is %v", err)
+	}
+	if balance := node.Balance(to); !balance.IsZero() {
+		t.Errorf("a failed transaction should not transfer but to has %s", balance)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, exitOK},
+		{flag.ErrHelp, exitOK},
+		{fmt.Errorf("%w: flag provided but not defined: -x", errUsage), exitUsage},
+		{errors.New("connection refused"), client.ExitError},
+		{fmt.Errorf("query account: %w", client.ErrAccountNotFound), client.ExitAccountNotFound},
+		{fmt.Errorf("chunk 1: %w", &client.TxError{Codespace: "sdk", Code: 32}), client.ExitSequenceMismatch},
+	}
+	for _, test := range tests {
+		sut := exitCode(test.err)
+
+		if sut != test.expected {
+			t.Errorf("exitCode of %v should be %d but is %d", test.err, test.expected, sut)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+const multisigUsage = `usage: submit-transaction multisig <command> [flags] [args]
+
+commands:
+  create   create a k-of-n multisig public key with -threshold k, the members are keys of the keyring or hex
+           secp256k1 public keys(as printed by keys show). The public key JSON is written to -out or stdout
+  show     show the address and members of a multisig public key file
+  sign     partially sign a transaction file with the -from member key, the multisig account number and sequence
+           are queried unless -offline
+  combine  combine at least threshold partial signature files into a transaction ready to broadcast
+
+the members sign with SIGN_MODE_LEGACY_AMINO_JSON, the sign bytes do not depend on the signatures of the others
+
+k-of-n transfer:
+  online:        submit-transaction multisig create -threshold 2 -out treasury.json alice bob 02A1...
+  online:        submit-transaction tx build -generate-only -multisig treasury.json -to <address> -out unsigned.json
+  each member:   submit-transaction multisig sign -multisig treasury.json -from <key> -out alice.json unsigned.json
+  online:        submit-transaction multisig combine -multisig treasury.json -out signed.json unsigned.json alice.json bob.json
+  online:        submit-transaction tx broadcast -wait signed.json`
+
+var multisigCommands = map[string]func(args []string) error{
+	"create":  runMultisigCreate,
+	"show":    runMultisigShow,
+	"sign":    runMultisigSign,
+	"combine": runMultisigCombine,
+}
+
+// multisig <command> [flags] [args]
+func runMultisig(args []string) error {
+	if len(args) == 0 {
+		return errors.New(multisigUsage)
+	}
+	command, ok := multisigCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("multisig: unknown command %q\n%s", args[0], multisigUsage)
+	}
+	return command(args[1:])
+}
+
+// multisig create -threshold k [-no-sort] [flags] <member>...
+func runMultisigCreate(args []string) error {
+	var p params
+	flags := newFlagSet("multisig create", &p)
+	threshold := flags.Int("threshold", 1, "number of member signatures needed to sign")
+	noSort := flags.Bool("no-sort", false, "keep the members in the given order, by default they are sorted by address")
+	out := flags.String("out", "", "file where the multisig public key JSON is written, stdout when empty")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	members := flags.Args()
+	if len(members) == 0 {
+		return errors.New(multisigUsage)
+	}
+	if *threshold <= 0 || *threshold > len(members) {
+		return fmt.Errorf("multisig create: threshold %d must be between 1 and the %d members", *threshold, len(members))
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	pubKeys := make([]cryptotypes.PubKey, 0, len(members))
+	for _, member := range members {
+		pubKey, err := resolvePubKey(kr, member)
+		if err != nil {
+			return fmt.Errorf("member %s: %w", member, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if !*noSort {
+		// same order as the cosmos-sdk keys add --multisig, the address does not depend on the order of the arguments
+		sortPubKeys(pubKeys)
+	}
+	pubKey := kmultisig.NewLegacyAminoPubKey(*threshold, pubKeys) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/crypto/keys/multisig#NewLegacyAminoPubKey
+
+	bz, err := client.NewCodec().MarshalInterfaceJSON(pubKey)
+	if err != nil {
+		return err
+	}
+	printMultisig(pubKey, net.Bech32)
+	if *out == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+	if err := os.WriteFile(*out, append(bz, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Multisig public key written to", *out)
+	return nil
+}
+
+// multisig show [flags] <file>
+func runMultisigShow(args []string) error {
+	var p params
+	flags := newFlagSet("multisig show", &p)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	file := flags.Arg(0)
+	if file == "" {
+		return errors.New(multisigUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	from, err := loadMultisig(file, net.Bech32)
+	if err != nil {
+		return err
+	}
+	printMultisig(from.pubKey.(*kmultisig.LegacyAminoPubKey), net.Bech32)
+	return nil
+}
+
+// multisig sign -multisig <file> -from <member> [-offline -account-number n -sequence n] [flags] <file>
+func runMultisigSign(args []string) error {
+	var p params
+	flags := newFlagSet("multisig sign", &p)
+	multisigFile := flags.String("multisig", "", "multisig public key file, created with multisig create")
+	signer := signerFlags(flags)
+	out := flags.String("out", "", "file where the partially signed JSON transaction is written, stdout when empty")
+	encoding := flags.String("encoding", client.EncodingJSON, "encoding of the written transaction: json, base64 or hex")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	file := flags.Arg(0)
+	if file == "" || *multisigFile == "" {
+		return errors.New(multisigUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	multisigAccount, err := loadMultisig(*multisigFile, net.Bech32)
+	if err != nil {
+		return err
+	}
+	txConfig := client.NewTxConfig()
+	txBuilder, err := readTx(txConfig, file)
+	if err != nil {
+		return err
+	}
+	if !isSigner(txBuilder.GetTx(), multisigAccount.address) {
+		return fmt.Errorf("multisig sign: %s is not a signer of the transaction", multisigAccount.bech32())
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	member, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	if memberIndex(multisigAccount.pubKey, member.pubKey) < 0 {
+		return fmt.Errorf("multisig sign: %s is not a member of %s", member.bech32(), multisigAccount.bech32())
+	}
+
+	// the member signs the sign bytes of the multisig account
+	accountNumber, sequence, err := signer.resolve(flags, net, multisigAccount.address)
+	if err != nil {
+		return err
+	}
+	if err := signAsMember(txConfig, txBuilder, net.ChainID, multisigAccount, member, accountNumber, sequence); err != nil {
+		return err
+	}
+	return writeTx(txConfig, txBuilder.GetTx(), *out, *encoding)
+}
+
+// multisig combine -multisig <file> [flags] <unsigned file> <partial file>...
+func runMultisigCombine(args []string) error {
+	var p params
+	flags := newFlagSet("multisig combine", &p)
+	multisigFile := flags.String("multisig", "", "multisig public key file, created with multisig create")
+	out := flags.String("out", "", "file where the signed JSON transaction is written, stdout when empty")
+	encoding := flags.String("encoding", client.EncodingJSON, "encoding of the written transaction: json, base64 or hex")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 || *multisigFile == "" {
+		return errors.New(multisigUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	multisigAccount, err := loadMultisig(*multisigFile, net.Bech32)
+	if err != nil {
+		return err
+	}
+	txConfig := client.NewTxConfig()
+	txBuilder, err := readTx(txConfig, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	sigV2, err := combineSignatures(txConfig, multisigAccount, flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if err := txBuilder.SetSignatures(sigV2); err != nil {
+		return err
+	}
+	return writeTx(txConfig, txBuilder.GetTx(), *out, *encoding)
+}
+
+// Set the partial signature of the member, it signs the sign bytes of the multisig account. The amino JSON sign mode
+// needs the address of the signer, unlike the direct mode.
+func signAsMember(txConfig sdkclient.TxConfig, txBuilder sdkclient.TxBuilder, chainID string, multisigAccount account, member account, accountNumber uint64, sequence uint64) error {
+	signerData := xauthsigning.SignerData{
+		Address:       multisigAccount.bech32(),
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+	}
+	sigV2, err := clienttx.SignWithPrivKey(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signerData, txBuilder, member.privKey, txConfig, sequence)
+	if err != nil {
+		return err
+	}
+	return txBuilder.SetSignatures(sigV2)
+}
+
+// Combine the member signatures of the partial files into a MultiSignatureData. All the partial signatures must be
+// for the same sequence, the threshold is checked so an incomplete transaction is not broadcasted.
+// https://pkg.go.dev/github.com/cosmos/cosmos-sdk/crypto/types/multisig#AddSignatureV2
+func combineSignatures(txConfig sdkclient.TxConfig, multisigAccount account, files []string) (signing.SignatureV2, error) {
+	pubKey := multisigAccount.pubKey.(*kmultisig.LegacyAminoPubKey)
+	data := multisig.NewMultisig(len(pubKey.PubKeys))
+	var sequence uint64
+	signed := 0
+	for i, file := range files {
+		partial, err := readTx(txConfig, file)
+		if err != nil {
+			return signing.SignatureV2{}, err
+		}
+		sigs, err := partial.GetTx().GetSignaturesV2()
+		if err != nil {
+			return signing.SignatureV2{}, fmt.Errorf("%s: %w", file, err)
+		}
+		if len(sigs) != 1 {
+			return signing.SignatureV2{}, fmt.Errorf("%s: expected 1 partial signature, got %d", file, len(sigs))
+		}
+		sig := sigs[0]
+		index := memberIndex(pubKey, sig.PubKey)
+		if index < 0 {
+			return signing.SignatureV2{}, fmt.Errorf("%s: signer is not a member of %s", file, multisigAccount.bech32())
+		}
+		if data.BitArray.GetIndex(index) {
+			return signing.SignatureV2{}, fmt.Errorf("%s: duplicated signature of member %d", file, index)
+		}
+		if i > 0 && sig.Sequence != sequence {
+			return signing.SignatureV2{}, fmt.Errorf("%s: signed for sequence %d, expected %d", file, sig.Sequence, sequence)
+		}
+		sequence = sig.Sequence
+		if err := multisig.AddSignatureV2(data, sig, pubKey.GetPubKeys()); err != nil {
+			return signing.SignatureV2{}, fmt.Errorf("%s: %w", file, err)
+		}
+		signed++
+	}
+	if signed < int(pubKey.Threshold) {
+		return signing.SignatureV2{}, fmt.Errorf("%d signatures, the threshold is %d", signed, pubKey.Threshold)
+	}
+	fmt.Fprintln(os.Stderr, "Combined", signed, "signatures of", len(pubKey.PubKeys), "members, sequence", sequence)
+	return signing.SignatureV2{PubKey: pubKey, Data: data, Sequence: sequence}, nil
+}
+
+// Load a multisig public key file as the account of a sender, it has no private key
+func loadMultisig(path string, prefixes client.Bech32Prefixes) (account, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return account{}, err
+	}
+	var pubKey cryptotypes.PubKey
+	if err := client.NewCodec().UnmarshalInterfaceJSON(bz, &pubKey); err != nil {
+		return account{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := pubKey.(*kmultisig.LegacyAminoPubKey); !ok {
+		return account{}, fmt.Errorf("%s: %T is not a multisig public key", path, pubKey)
+	}
+	return account{pubKey: pubKey, address: sdk.AccAddress(pubKey.Address()), prefixes: prefixes}, nil
+}
+
+// A member is the name of a key in the keyring or a hex compressed secp256k1 public key
+func resolvePubKey(kr *keyring.Keyring, nameOrPubKey string) (cryptotypes.PubKey, error) {
+	if bz, err := hex.DecodeString(nameOrPubKey); err == nil && len(bz) == secp256k1.PubKeySize {
+		return &secp256k1.PubKey{Key: bz}, nil
+	}
+	info, err := kr.Show(nameOrPubKey)
+	if err != nil {
+		return nil, err
+	}
+	return info.PubKey, nil
+}
+
+// Sort the members by address
+func sortPubKeys(pubKeys []cryptotypes.PubKey) {
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i].Address(), pubKeys[j].Address()) < 0
+	})
+}
+
+// Index of the member in the multisig, -1 when it is not a member
+func memberIndex(multisigPubKey cryptotypes.PubKey, member cryptotypes.PubKey) int {
+	pubKey, ok := multisigPubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok || member == nil {
+		return -1
+	}
+	for i, pk := range pubKey.GetPubKeys() {
+		if pk.Equals(member) {
+			return i
+		}
+	}
+	return -1
+}
+
+func printMultisig(pubKey *kmultisig.LegacyAminoPubKey, prefixes client.Bech32Prefixes) {
+	fmt.Printf("Multisig %d-of-%d\t%s\n", pubKey.Threshold, len(pubKey.PubKeys), prefixes.AccAddress(sdk.AccAddress(pubKey.Address())))
+	for i, pk := range pubKey.GetPubKeys() {
+		fmt.Printf(" %d\t%s\t%X\n", i, prefixes.AccAddress(sdk.AccAddress(pk.Address())), pk.Bytes())
+	}
+}
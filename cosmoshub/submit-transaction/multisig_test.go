@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cosmoshub/client"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// a threshold-of-n multisig account and its members, in the order of the multisig public key
+func newTestMultisig(threshold int, n int) (account, []account) {
+	members := make([]account, n)
+	for i := range members {
+		members[i] = newTestAccount()
+	}
+	pubKeys := make([]cryptotypes.PubKey, n)
+	for i, member := range members {
+		pubKeys[i] = member.pubKey
+	}
+	pubKey := kmultisig.NewLegacyAminoPubKey(threshold, pubKeys)
+	return account{pubKey: pubKey, address: sdk.AccAddress(pubKey.Address()), prefixes: client.CosmosPrefixes}, members
+}
+
+// the unsigned transaction file of a transfer from the multisig account
+func writeUnsignedTx(t *testing.T, txConfig sdkclient.TxConfig, from account) string {
+	txBuilder, err := createTransaction(txConfig, nil, newMsgSend(from.address, sdk.AccAddress([]byte("to__________________")), sdk.NewInt64Coin("uatom", 10000)))
+	if err != nil {
+		t.Fatalf("createTransaction error %v", err)
+	}
+	txBuilder.SetGasLimit(200000)
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("uatom", 5000)))
+	path := filepath.Join(t.TempDir(), "unsigned.json")
+	if err := writeTx(txConfig, txBuilder.GetTx(), path, client.EncodingJSON); err != nil {
+		t.Fatalf("writeTx error %v", err)
+	}
+	return path
+}
+
+// the partial signature file of the member and its signature, as written by multisig sign
+func writePartialTx(t *testing.T, txConfig sdkclient.TxConfig, unsigned string, multisigAccount account, member account, accountNumber uint64) (string, []byte) {
+	txBuilder, err := readTx(txConfig, unsigned)
+	if err != nil {
+		t.Fatalf("readTx error %v", err)
+	}
+	if err := signAsMember(txConfig, txBuilder, testNetwork.ChainID, multisigAccount, member, accountNumber, 0); err != nil {
+		t.Fatalf("signAsMember error %v", err)
+	}
+	sigs, err := txBuilder.GetTx().GetSignaturesV2()
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("the partial transaction should have 1 signature but has %v %v", sigs, err)
+	}
+	path := filepath.Join(t.TempDir(), "partial.json")
+	if err := writeTx(txConfig, txBuilder.GetTx(), path, client.EncodingJSON); err != nil {
+		t.Fatalf("writeTx error %v", err)
+	}
+	return path, sigs[0].Data.(*signing.SingleSignatureData).Signature
+}
+
+func TestCombineSignatures(t *testing.T) {
+	node, c := startNode(t)
+	txConfig := client.NewTxConfig()
+	multisigAccount, members := newTestMultisig(2, 3)
+	number := node.AddAccount(multisigAccount.address, sdk.NewInt64Coin("uatom", 1000000))
+	unsigned := writeUnsignedTx(t, txConfig, multisigAccount)
+	partial2, sig2 := writePartialTx(t, txConfig, unsigned, multisigAccount, members[2], number)
+	partial0, sig0 := writePartialTx(t, txConfig, unsigned, multisigAccount, members[0], number)
+
+	sut, err := combineSignatures(txConfig, multisigAccount, []string{partial2, partial0})
+
+	if err != nil {
+		t.Fatalf("combineSignatures error %v", err)
+	}
+	data, ok := sut.Data.(*signing.MultiSignatureData)
+	if !ok {
+		t.Fatalf("the combined signature should be a MultiSignatureData but is %T", sut.Data)
+	}
+	if !data.BitArray.GetIndex(0) || data.BitArray.GetIndex(1) || !data.BitArray.GetIndex(2) {
+		t.Errorf("the bit array should mark the members 0 and 2 but is %s", data.BitArray)
+	}
+	// the signatures are in the order of the members, not of the files
+	if len(data.Signatures) != 2 ||
+		!bytes.Equal(data.Signatures[0].(*signing.SingleSignatureData).Signature, sig0) ||
+		!bytes.Equal(data.Signatures[1].(*signing.SingleSignatureData).Signature, sig2) {
+		t.Errorf("the signatures should be the ones of the members 0 and 2 but are %v", data.Signatures)
+	}
+
+	// the node verifies the multisignature against the multisig public key
+	txBuilder, err := readTx(txConfig, unsigned)
+	if err != nil {
+		t.Fatalf("readTx error %v", err)
+	}
+	if err := txBuilder.SetSignatures(sut); err != nil {
+		t.Fatalf("SetSignatures error %v", err)
+	}
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatalf("TxEncoder error %v", err)
+	}
+	txRes, err := c.Broadcast(context.Background(), txBytes)
+	if err != nil || txRes.Code != 0 {
+		t.Fatalf("the combined transaction should be accepted but is %v %v", txRes, err)
+	}
+	if node.Sequence(multisigAccount.address) != 1 {
+		t.Errorf("the sequence of the multisig account should be incremented but is %d", node.Sequence(multisigAccount.address))
+	}
+}
+
+func TestCombineSignaturesErrors(t *testing.T) {
+	txConfig := client.NewTxConfig()
+	multisigAccount, members := newTestMultisig(2, 3)
+	unsigned := writeUnsignedTx(t, txConfig, multisigAccount)
+	partial0, _ := writePartialTx(t, txConfig, unsigned, multisigAccount, members[0], 1)
+	partial1, _ := writePartialTx(t, txConfig, unsigned, multisigAccount, members[1], 1)
+	other, _ := writePartialTx(t, txConfig, unsigned, multisigAccount, newTestAccount(), 1)
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{"non member", []string{partial0, other}, "is not a member"},
+		{"duplicated member", []string{partial1, partial1}, "duplicated signature of member 1"},
+		{"under the threshold", []string{partial1}, "1 signatures, the threshold is 2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := combineSignatures(txConfig, multisigAccount, test.files)
+
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("combineSignatures should fail with %q but returns %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestSortPubKeys(t *testing.T) {
+	sut := []cryptotypes.PubKey{newTestAccount().pubKey, newTestAccount().pubKey, newTestAccount().pubKey, newTestAccount().pubKey}
+
+	sortPubKeys(sut)
+
+	for i := 1; i < len(sut); i++ {
+		if bytes.Compare(sut[i-1].Address(), sut[i].Address()) >= 0 {
+			t.Errorf("the public keys should be sorted by address but %d is %s and %d is %s", i-1, sut[i-1].Address(), i, sut[i].Address())
+		}
+	}
+}
+
+func TestMemberIndex(t *testing.T) {
+	multisigAccount, members := newTestMultisig(2, 3)
+
+	for i, member := range members {
+		if sut := memberIndex(multisigAccount.pubKey, member.pubKey); sut != i {
+			t.Errorf("member %d should have the index %d but has %d", i, i, sut)
+		}
+	}
+	if sut := memberIndex(multisigAccount.pubKey, newTestAccount().pubKey); sut != -1 {
+		t.Errorf("a non member should have the index -1 but has %d", sut)
+	}
+	if sut := memberIndex(members[0].pubKey, members[0].pubKey); sut != -1 {
+		t.Errorf("a key that is not a multisig should have no members but has the index %d", sut)
+	}
+	if sut := memberIndex(multisigAccount.pubKey, nil); sut != -1 {
+		t.Errorf("a nil member should have the index -1 but has %d", sut)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const portfolioUsage = `usage: submit-transaction portfolio -file <addresses.txt> [flags]
+
+queries the balances, delegations, unbonding delegations and pending rewards of the addresses of the file and
+reports the amounts of each address and denom with the totals of all of them. The file has a key or bech32 address
+per line with an optional label after a comma, the empty lines and the ones starting with # are skipped:
+  cosmos1...,treasury
+  alice`
+
+// concurrent addresses queried, each one runs 4 queries
+const defaultPortfolioWorkers = 4
+
+// Queries of the portfolio, *client.Client implements them
+type portfolioChain interface {
+	GetAllBalances(ctx context.Context, address sdk.AccAddress, opts ...client.PageOption) (sdk.Coins, error)
+	Delegations(ctx context.Context, delegator sdk.AccAddress, opts ...client.PageOption) ([]stakingtypes.DelegationResponse, error)
+	UnbondingDelegations(ctx context.Context, delegator sdk.AccAddress, opts ...client.PageOption) ([]stakingtypes.UnbondingDelegation, error)
+	Rewards(ctx context.Context, delegator sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error)
+}
+
+// A line of the address list file
+type portfolioAddress struct {
+	label   string
+	address sdk.AccAddress
+}
+
+// The amounts of a denom held by an address or by all of them, in the base denom
+type denomAmounts struct {
+	Denom     string   `json:"denom"`
+	Available math.Int `json:"available"`
+	Delegated math.Int `json:"delegated"`
+	Unbonding math.Int `json:"unbonding"`
+	Rewards   math.Int `json:"rewards"` // the decimals of the pending rewards are truncated
+	Total     math.Int `json:"total"`
+}
+
+// The report of an address, Error is set when one of its queries failed
+type holding struct {
+	Label   string         `json:"label,omitempty"`
+	Address string         `json:"address"`
+	Amounts []denomAmounts `json:"amounts"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// portfolio -file <addresses.txt> [-workers n] [-format table|csv|json] [-out file] [flags]
+func runPortfolio(args []string) error {
+	var p params
+	flags := newFlagSet("portfolio", &p)
+	file := flags.String("file", "", "file with a key or bech32 address per line and an optional label after a comma")
+	workers := flags.Int("workers", defaultPortfolioWorkers, "addresses queried concurrently")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	out := flags.String("out", "", "file where the report is written, stdout when empty")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New(portfolioUsage)
+	}
+	if *workers <= 0 {
+		return fmt.Errorf("portfolio: invalid -workers %d", *workers)
+	}
+	if *format != "table" && *format != "csv" && *format != "json" {
+		return fmt.Errorf("portfolio: unknown -format %q", *format)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	addresses, err := loadAddressList(*file, kr, net.Bech32)
+	if err != nil {
+		return fmt.Errorf("portfolio: %w", err)
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// the unbonding entries have no denom, they are in the bond denom of the chain: the network denom
+	holdings := queryPortfolio(context.Background(), c, net.Bech32, net.Denom, addresses, *workers)
+	failed := 0
+	for _, h := range holdings {
+		if h.Error != "" {
+			failed++
+			fmt.Fprintln(os.Stderr, "Query error", h.Address, h.Error)
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writePortfolio(w, holdings, portfolioTotals(holdings), *format); err != nil {
+		return fmt.Errorf("portfolio: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintln(os.Stderr, len(holdings), "addresses written to", *out)
+	}
+	if failed > 0 {
+		return fmt.Errorf("portfolio: the queries of %d addresses failed, they are not in the totals", failed)
+	}
+	return nil
+}
+
+// Read the address list, the keys are resolved to their address in the keyring
+func loadAddressList(path string, kr *keyring.Keyring, prefixes client.Bech32Prefixes) ([]portfolioAddress, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var addresses []portfolioAddress
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		nameOrAddress, label, _ := strings.Cut(text, ",")
+		nameOrAddress = strings.TrimSpace(nameOrAddress)
+		address, err := prefixes.ParseAccAddress(nameOrAddress)
+		if err != nil {
+			info, showErr := kr.Show(nameOrAddress)
+			if showErr != nil {
+				return nil, fmt.Errorf("%s line %d: %s is not an address or a key: %w", path, line, nameOrAddress, showErr)
+			}
+			address = info.Address
+		}
+		addresses = append(addresses, portfolioAddress{label: strings.TrimSpace(label), address: address})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s has no addresses", path)
+	}
+	return addresses, nil
+}
+
+// Query the addresses with a pool of workers, the holdings keep the order of the addresses
+func queryPortfolio(ctx context.Context, chain portfolioChain, prefixes client.Bech32Prefixes, bondDenom string, addresses []portfolioAddress, workers int) []holding {
+	holdings := make([]holding, len(addresses))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(addresses); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				holdings[j] = queryHolding(ctx, chain, prefixes, bondDenom, addresses[j])
+			}
+		}()
+	}
+	for i := range addresses {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return holdings
+}
+
+func queryHolding(ctx context.Context, chain portfolioChain, prefixes client.Bech32Prefixes, bondDenom string, a portfolioAddress) holding {
+	h := holding{Label: a.label, Address: prefixes.AccAddress(a.address)}
+	available, err := chain.GetAllBalances(ctx, a.address)
+	if err != nil {
+		h.Error = err.Error()
+		return h
+	}
+	delegations, err := chain.Delegations(ctx, a.address)
+	if err != nil {
+		h.Error = err.Error()
+		return h
+	}
+	var delegated sdk.Coins
+	for _, delegation := range delegations {
+		delegated = delegated.Add(delegation.Balance)
+	}
+	unbondings, err := chain.UnbondingDelegations(ctx, a.address)
+	if err != nil {
+		h.Error = err.Error()
+		return h
+	}
+	var unbonding sdk.Coins
+	for _, unbondingDelegation := range unbondings {
+		for _, entry := range unbondingDelegation.Entries {
+			unbonding = unbonding.Add(sdk.NewCoin(bondDenom, entry.Balance))
+		}
+	}
+	rewards, err := chain.Rewards(ctx, a.address)
+	if err != nil {
+		h.Error = err.Error()
+		return h
+	}
+	truncated, _ := rewards.Total.TruncateDecimal()
+	h.Amounts = newDenomAmounts(available, delegated, unbonding, truncated)
+	return h
+}
+
+// The amounts of each denom of the coins sorted by denom
+func newDenomAmounts(available sdk.Coins, delegated sdk.Coins, unbonding sdk.Coins, rewards sdk.Coins) []denomAmounts {
+	denoms := map[string]bool{}
+	for _, coins := range []sdk.Coins{available, delegated, unbonding, rewards} {
+		for _, coin := range coins {
+			denoms[coin.Denom] = true
+		}
+	}
+	var amounts []denomAmounts
+	for denom := range denoms {
+		a := denomAmounts{
+			Denom:     denom,
+			Available: available.AmountOf(denom),
+			Delegated: delegated.AmountOf(denom),
+			Unbonding: unbonding.AmountOf(denom),
+			Rewards:   rewards.AmountOf(denom),
+		}
+		a.Total = a.Available.Add(a.Delegated).Add(a.Unbonding).Add(a.Rewards)
+		amounts = append(amounts, a)
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].Denom < amounts[j].Denom })
+	return amounts
+}
+
+// The sum of the amounts of each denom of all the holdings, the failed ones are not added
+func portfolioTotals(holdings []holding) []denomAmounts {
+	totals := map[string]*denomAmounts{}
+	var denoms []string
+	for _, h := range holdings {
+		for _, a := range h.Amounts {
+			total, ok := totals[a.Denom]
+			if !ok {
+				zero := math.ZeroInt()
+				total = &denomAmounts{Denom: a.Denom, Available: zero, Delegated: zero, Unbonding: zero, Rewards: zero, Total: zero}
+				totals[a.Denom] = total
+				denoms = append(denoms, a.Denom)
+			}
+			total.Available = total.Available.Add(a.Available)
+			total.Delegated = total.Delegated.Add(a.Delegated)
+			total.Unbonding = total.Unbonding.Add(a.Unbonding)
+			total.Rewards = total.Rewards.Add(a.Rewards)
+			total.Total = total.Total.Add(a.Total)
+		}
+	}
+	sort.Strings(denoms)
+	result := []denomAmounts{}
+	for _, denom := range denoms {
+		result = append(result, *totals[denom])
+	}
+	return result
+}
+
+// Write a row per address and denom followed by the totals as a table, CSV with a header or a JSON object
+func writePortfolio(w io.Writer, holdings []holding, totals []denomAmounts, format string) error {
+	row := func(label string, address string, a denomAmounts) []string {
+		return []string{label, address, a.Denom, a.Available.String(), a.Delegated.String(), a.Unbonding.String(), a.Rewards.String(), a.Total.String()}
+	}
+	switch format {
+	case "table":
+		fmt.Fprintln(w, "label\taddress\tdenom\tavailable\tdelegated\tunbonding\trewards\ttotal")
+		for _, h := range holdings {
+			if h.Error != "" {
+				fmt.Fprintf(w, "%s\t%s\terror %s\n", h.Label, h.Address, h.Error)
+			}
+			for _, a := range h.Amounts {
+				fmt.Fprintln(w, strings.Join(row(h.Label, h.Address, a), "\t"))
+			}
+		}
+		for _, a := range totals {
+			fmt.Fprintln(w, strings.Join(row("total", "", a), "\t"))
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w) // https://pkg.go.dev/encoding/csv#Writer
+		cw.Write([]string{"label", "address", "denom", "available", "delegated", "unbonding", "rewards", "total", "error"})
+		for _, h := range holdings {
+			if h.Error != "" {
+				cw.Write([]string{h.Label, h.Address, "", "", "", "", "", "", h.Error})
+			}
+			for _, a := range h.Amounts {
+				cw.Write(append(row(h.Label, h.Address, a), ""))
+			}
+		}
+		for _, a := range totals {
+			cw.Write(append(row("total", "", a), ""))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Addresses []holding      `json:"addresses"`
+			Totals    []denomAmounts `json:"totals"`
+		}{holdings, totals})
+	default:
+		return fmt.Errorf("unknown -format %q", format)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// simulated chain: every address holds the same amounts except the failing one, the concurrent queries are counted
+type fakePortfolioChain struct {
+	failing       sdk.AccAddress
+	mu            sync.Mutex
+	running       int
+	maxConcurrent int
+}
+
+func (f *fakePortfolioChain) GetAllBalances(ctx context.Context, address sdk.AccAddress, opts ...client.PageOption) (sdk.Coins, error) {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.maxConcurrent {
+		f.maxConcurrent = f.running
+	}
+	f.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	if address.Equals(f.failing) {
+		return nil, errors.New("unavailable")
+	}
+	return sdk.NewCoins(sdk.NewInt64Coin("uatom", 100), sdk.NewInt64Coin("uosmo", 7)), nil
+}
+
+func (f *fakePortfolioChain) Delegations(ctx context.Context, delegator sdk.AccAddress, opts ...client.PageOption) ([]stakingtypes.DelegationResponse, error) {
+	return []stakingtypes.DelegationResponse{
+		{Balance: sdk.NewInt64Coin("uatom", 1000)},
+		{Balance: sdk.NewInt64Coin("uatom", 500)},
+	}, nil
+}
+
+func (f *fakePortfolioChain) UnbondingDelegations(ctx context.Context, delegator sdk.AccAddress, opts ...client.PageOption) ([]stakingtypes.UnbondingDelegation, error) {
+	return []stakingtypes.UnbondingDelegation{{Entries: []stakingtypes.UnbondingDelegationEntry{{Balance: math.NewInt(20)}, {Balance: math.NewInt(30)}}}}, nil
+}
+
+func (f *fakePortfolioChain) Rewards(ctx context.Context, delegator sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	return &distrtypes.QueryDelegationTotalRewardsResponse{Total: sdk.NewDecCoins(sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("3.75")))}, nil
+}
+
+func testAddresses(n int) []portfolioAddress {
+	var addresses []portfolioAddress
+	for i := 0; i < n; i++ {
+		address := make([]byte, 20)
+		address[0] = byte(i + 1)
+		addresses = append(addresses, portfolioAddress{label: strings.Repeat("a", i+1), address: address})
+	}
+	return addresses
+}
+
+func TestQueryPortfolio(t *testing.T) {
+	addresses := testAddresses(10)
+	chain := &fakePortfolioChain{failing: addresses[3].address}
+
+	sut := queryPortfolio(context.Background(), chain, testPrefixes, "uatom", addresses, 3)
+
+	if len(sut) != 10 || sut[9].Label != "aaaaaaaaaa" || sut[9].Address != testPrefixes.AccAddress(addresses[9].address) {
+		t.Fatalf("queryPortfolio should return the holdings in the order of the addresses but returns %+v", sut)
+	}
+	if chain.maxConcurrent > 3 || chain.maxConcurrent < 2 {
+		t.Errorf("the addresses should be queried by at most 3 workers but %d run at the same time", chain.maxConcurrent)
+	}
+	if sut[3].Error != "unavailable" || sut[3].Amounts != nil {
+		t.Errorf("the failing address should have the error but is %+v", sut[3])
+	}
+	atom := sut[0].Amounts[0]
+	if atom.Denom != "uatom" || atom.Available.Int64() != 100 || atom.Delegated.Int64() != 1500 || atom.Unbonding.Int64() != 50 || atom.Rewards.Int64() != 3 || atom.Total.Int64() != 1653 {
+		t.Errorf("uatom should be available 100, delegated 1500, unbonding 50 and rewards 3 but is %+v", atom)
+	}
+	totals := portfolioTotals(sut)
+	if len(totals) != 2 || totals[0].Total.Int64() != 9*1653 || totals[1].Denom != "uosmo" || totals[1].Available.Int64() != 63 {
+		t.Errorf("the totals should add the 9 addresses without error but are %+v", totals)
+	}
+}
+
+func TestWritePortfolioCSV(t *testing.T) {
+	holdings := []holding{
+		{Label: "treasury", Address: "cosmos1a", Amounts: newDenomAmounts(sdk.NewCoins(sdk.NewInt64Coin("uatom", 5)), nil, nil, nil)},
+		{Address: "cosmos1b", Error: "unavailable"},
+	}
+	var out bytes.Buffer
+
+	err := writePortfolio(&out, holdings, portfolioTotals(holdings), "csv")
+
+	if err != nil {
+		t.Fatalf("writePortfolio error %v", err)
+	}
+	expected := "label,address,denom,available,delegated,unbonding,rewards,total,error\n" +
+		"treasury,cosmos1a,uatom,5,0,0,0,5,\n" +
+		",cosmos1b,,,,,,,unavailable\n" +
+		"total,,uatom,5,0,0,0,5,\n"
+	if out.String() != expected {
+		t.Errorf("writePortfolio should write\n%s\nbut writes\n%s", expected, out.String())
+	}
+}
+
+func TestRunPortfolioUnknownFormat(t *testing.T) {
+	// the file does not exist, the format is checked before reading it and querying the node
+	sut := runPortfolio([]string{"-file", filepath.Join(t.TempDir(), "missing.txt"), "-format", "xml"})
+
+	if sut == nil || sut.Error() != `portfolio: unknown -format "xml"` {
+		t.Errorf("runPortfolio should fail with the unknown format but returns %v", sut)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	"cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const restakeUsage = `usage: submit-transaction restake -from <grantee key> -validator <address> [flags]
+
+runs as the grantee of the delegators that authorized it, every -interval it claims the rewards of each granter
+from -validator and delegates them again to -validator with a MsgExec. A granter is restaked when:
+  - it granted a generic authorization of /cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward
+  - it granted a delegate stake authorization that allows -validator(or a generic one of MsgDelegate)
+  - its pending rewards in the network denom are at least -min-reward, the amount is capped by max tokens
+
+the granters authorize the bot with:
+  submit-transaction authz grant -from <granter> -grantee <bot> -type withdraw-rewards
+  submit-transaction authz grant -from <granter> -grantee <bot> -type delegate -allow <validator>`
+
+// Chain operations of the restake bot, the grantee signs the transactions
+type restakeChain interface {
+	GranteeGrants(ctx context.Context, grantee sdk.AccAddress, opts ...client.PageOption) ([]*authz.GrantAuthorization, error)
+	UnpackAuthorization(any *codectypes.Any) (authz.Authorization, error)
+	Rewards(ctx context.Context, delegator sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error)
+	Send(msgs ...sdk.Msg) (*sdk.TxResponse, error)
+}
+
+// The client for the queries and the sender queue of the grantee for the transactions
+type queueChain struct {
+	*client.Client
+	queue *senderQueue
+}
+
+func (q queueChain) Send(msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	return q.queue.send("restake", msgs...)
+}
+
+// A granter to restake and the amount of its rewards delegated again
+type restakeTarget struct {
+	granter sdk.AccAddress
+	amount  math.Int
+}
+
+// Claims the rewards of the granters and delegates them to validator
+type restaker struct {
+	chain     restakeChain
+	grantee   sdk.AccAddress
+	validator sdk.ValAddress
+	prefixes  client.Bech32Prefixes
+	denom     string
+	minReward math.Int
+	now       func() time.Time
+}
+
+// restake -from <grantee key> -validator <address> [-min-reward n] [-interval d] [-once] [flags]
+func runRestake(args []string) error {
+	var p stakingParams
+	flags := newStakingFlagSet("restake", &p)
+	minReward := flags.Int64("min-reward", 10000, "min pending rewards in the network denom to restake a granter, small amounts are not worth the fee")
+	interval := flags.Duration("interval", time.Hour, "time between restake runs")
+	once := flags.Bool("once", false, "run once and exit")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if p.validator == "" {
+		return errors.New(restakeUsage)
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+	validator, err := net.Bech32.ParseValAddress(p.validator)
+	if err != nil {
+		return fmt.Errorf("-validator: %w", err)
+	}
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	grantee, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	queue := newSenderQueue(c, net, grantee, p.dryRun, 1)
+	defer queue.close()
+
+	r := &restaker{
+		chain:     queueChain{Client: c, queue: queue},
+		grantee:   grantee.address,
+		validator: validator,
+		prefixes:  net.Bech32,
+		denom:     net.Denom,
+		minReward: math.NewInt(*minReward),
+		now:       time.Now,
+	}
+
+	// the bot runs until it is interrupted, a failed run is retried on the next tick
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		fmt.Println("Restake run", time.Now().Format(time.RFC3339))
+		if err := r.run(ctx); err != nil {
+			if *once {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "Restake error", err)
+		}
+		if *once {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Restake every granter found by plan, each one in its own transaction so a failure does not stop the others
+func (r *restaker) run(ctx context.Context) error {
+	targets, err := r.plan(ctx)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, target := range targets {
+		granter := r.prefixes.AccAddress(target.granter)
+		fmt.Println("Restaking", target.amount.String()+r.denom, "of", granter)
+		txRes, err := r.chain.Send(r.restakeMsg(target))
+		if err == nil {
+			err = client.CheckTxResponse(txRes)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Restake", granter, "error", err)
+			failed++
+		}
+	}
+	fmt.Println("Restaked", len(targets)-failed, "of", len(targets), "granters")
+	if failed > 0 {
+		return fmt.Errorf("%d restakes failed", failed)
+	}
+	return nil
+}
+
+// The granters that authorized the grantee to withdraw and delegate to validator and whose rewards reach the minimum
+func (r *restaker) plan(ctx context.Context) ([]restakeTarget, error) {
+	grants, err := r.chain.GranteeGrants(ctx, r.grantee)
+	if err != nil {
+		return nil, err
+	}
+
+	// the withdraw and delegate grants of each granter, in the order of the grants
+	type granterGrants struct {
+		withdraw  bool
+		delegate  bool
+		maxTokens *sdk.Coin
+	}
+	var granters []string
+	byGranter := map[string]*granterGrants{}
+	for _, grant := range grants {
+		if grant.Expiration != nil && grant.Expiration.Before(r.now()) {
+			continue
+		}
+		authorization, err := r.chain.UnpackAuthorization(grant.Authorization)
+		if err != nil {
+			return nil, err
+		}
+		g, ok := byGranter[grant.Granter]
+		if !ok {
+			g = &granterGrants{}
+			byGranter[grant.Granter] = g
+			granters = append(granters, grant.Granter)
+		}
+		switch a := authorization.(type) {
+		case *authz.GenericAuthorization:
+			g.withdraw = g.withdraw || a.Msg == sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{})
+			g.delegate = g.delegate || a.Msg == sdk.MsgTypeURL(&stakingtypes.MsgDelegate{})
+		case *stakingtypes.StakeAuthorization:
+			if a.AuthorizationType == stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE && r.allows(a) {
+				g.delegate, g.maxTokens = true, a.MaxTokens
+			}
+		}
+	}
+
+	var targets []restakeTarget
+	for _, address := range granters {
+		g := byGranter[address]
+		if !g.withdraw || !g.delegate {
+			continue
+		}
+		granter, err := r.prefixes.ParseAccAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := r.pendingReward(ctx, granter)
+		if err != nil {
+			return nil, err
+		}
+		if g.maxTokens != nil && amount.GT(g.maxTokens.Amount) {
+			amount = g.maxTokens.Amount
+		}
+		if amount.IsZero() || amount.LT(r.minReward) {
+			continue
+		}
+		targets = append(targets, restakeTarget{granter: granter, amount: amount})
+	}
+	return targets, nil
+}
+
+// A stake authorization allows the validator when it is in the allow list or not in the deny list
+func (r *restaker) allows(a *stakingtypes.StakeAuthorization) bool {
+	validator := r.prefixes.ValAddress(r.validator)
+	contains := func(addresses []string) bool {
+		for _, address := range addresses {
+			if address == validator {
+				return true
+			}
+		}
+		return false
+	}
+	if allow := a.GetAllowList(); allow != nil {
+		return contains(allow.Address)
+	}
+	if deny := a.GetDenyList(); deny != nil {
+		return !contains(deny.Address)
+	}
+	return true
+}
+
+// The rewards of the granter from validator in denom, truncated to an integer amount
+func (r *restaker) pendingReward(ctx context.Context, granter sdk.AccAddress) (math.Int, error) {
+	rewards, err := r.chain.Rewards(ctx, granter)
+	if err != nil {
+		return math.Int{}, err
+	}
+	validator := r.prefixes.ValAddress(r.validator)
+	for _, reward := range rewards.Rewards {
+		if reward.ValidatorAddress == validator {
+			return reward.Reward.AmountOf(r.denom).TruncateInt(), nil
+		}
+	}
+	return math.ZeroInt(), nil
+}
+
+// The grantee executes the withdraw and the delegation on behalf of the granter
+func (r *restaker) restakeMsg(target restakeTarget) sdk.Msg {
+	exec := authz.NewMsgExec(r.grantee, []sdk.Msg{
+		distrtypes.NewMsgWithdrawDelegatorReward(target.granter, r.validator),
+		stakingtypes.NewMsgDelegate(target.granter, r.validator, sdk.NewCoin(r.denom, target.amount)),
+	})
+	return &exec
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	"cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+var (
+	testPrefixes   = client.CosmosPrefixes
+	testNow        = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	testGrantee    = sdk.AccAddress([]byte("grantee_____________"))
+	testValidator  = sdk.ValAddress([]byte("validator___________"))
+	testValidator2 = sdk.ValAddress([]byte("validator2__________"))
+)
+
+// simulated chain: the grants and rewards are fixed and the sent transactions are recorded
+type fakeChain struct {
+	grants  []*authz.GrantAuthorization
+	rewards map[string]sdk.DecCoins // granter -> rewards from testValidator
+	sent    [][]sdk.Msg
+	sendErr error
+}
+
+func (f *fakeChain) GranteeGrants(ctx context.Context, grantee sdk.AccAddress, opts ...client.PageOption) ([]*authz.GrantAuthorization, error) {
+	return f.grants, nil
+}
+
+func (f *fakeChain) UnpackAuthorization(any *codectypes.Any) (authz.Authorization, error) {
+	return any.GetCachedValue().(authz.Authorization), nil
+}
+
+func (f *fakeChain) Rewards(ctx context.Context, delegator sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	res := &distrtypes.QueryDelegationTotalRewardsResponse{}
+	if reward, ok := f.rewards[testPrefixes.AccAddress(delegator)]; ok {
+		res.Rewards = append(res.Rewards, distrtypes.DelegationDelegatorReward{ValidatorAddress: testPrefixes.ValAddress(testValidator), Reward: reward})
+	}
+	return res, nil
+}
+
+func (f *fakeChain) Send(msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	f.sent = append(f.sent, msgs)
+	return &sdk.TxResponse{}, f.sendErr
+}
+
+func (f *fakeChain) grant(granter sdk.AccAddress, authorization authz.Authorization, expiration time.Time) {
+	any, err := codectypes.NewAnyWithValue(authorization)
+	if err != nil {
+		panic(err)
+	}
+	f.grants = append(f.grants, &authz.GrantAuthorization{
+		Granter:       testPrefixes.AccAddress(granter),
+		Grantee:       testPrefixes.AccAddress(testGrantee),
+		Authorization: any,
+		Expiration:    &expiration,
+	})
+}
+
+// grants the withdraw and a delegate authorization limited to the allowed validators
+func (f *fakeChain) grantRestake(granter sdk.AccAddress, maxTokens *sdk.Coin, allowed ...sdk.ValAddress) {
+	stakeAuth, err := stakingtypes.NewStakeAuthorization(allowed, nil, stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, maxTokens)
+	if err != nil {
+		panic(err)
+	}
+	f.grant(granter, authz.NewGenericAuthorization(sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{})), testNow.Add(time.Hour))
+	f.grant(granter, stakeAuth, testNow.Add(time.Hour))
+}
+
+func (f *fakeChain) reward(granter sdk.AccAddress, amount int64) {
+	if f.rewards == nil {
+		f.rewards = map[string]sdk.DecCoins{}
+	}
+	f.rewards[testPrefixes.AccAddress(granter)] = sdk.NewDecCoins(sdk.NewDecCoin("uatom", math.NewInt(amount)))
+}
+
+func newTestRestaker(chain restakeChain) *restaker {
+	return &restaker{
+		chain:     chain,
+		grantee:   testGrantee,
+		validator: testValidator,
+		prefixes:  testPrefixes,
+		denom:     "uatom",
+		minReward: math.NewInt(1000),
+		now:       func() time.Time { return testNow },
+	}
+}
+
+func TestRestakePlan(t *testing.T) {
+	granted := sdk.AccAddress([]byte("granted_____________"))
+	small := sdk.AccAddress([]byte("small_______________"))
+	capped := sdk.AccAddress([]byte("capped______________"))
+	otherValidator := sdk.AccAddress([]byte("other_validator_____"))
+	withdrawOnly := sdk.AccAddress([]byte("withdraw_only_______"))
+	expired := sdk.AccAddress([]byte("expired_____________"))
+
+	chain := &fakeChain{}
+	chain.grantRestake(granted, nil, testValidator)
+	chain.reward(granted, 5000)
+	chain.grantRestake(small, nil, testValidator)
+	chain.reward(small, 999)
+	maxTokens := sdk.NewInt64Coin("uatom", 2000)
+	chain.grantRestake(capped, &maxTokens, testValidator)
+	chain.reward(capped, 5000)
+	chain.grantRestake(otherValidator, nil, testValidator2)
+	chain.reward(otherValidator, 5000)
+	chain.grant(withdrawOnly, authz.NewGenericAuthorization(sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{})), testNow.Add(time.Hour))
+	chain.reward(withdrawOnly, 5000)
+	chain.grant(expired, authz.NewGenericAuthorization(sdk.MsgTypeURL(&distrtypes.MsgWithdrawDelegatorReward{})), testNow.Add(-time.Hour))
+	chain.grant(expired, authz.NewGenericAuthorization(sdk.MsgTypeURL(&stakingtypes.MsgDelegate{})), testNow.Add(-time.Hour))
+	chain.reward(expired, 5000)
+
+	sut := newTestRestaker(chain)
+	targets, err := sut.plan(context.Background())
+
+	if err != nil {
+		t.Fatalf("plan error %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("plan should restake granted and capped but restakes %v", targets)
+	}
+	if !targets[0].granter.Equals(granted) || !targets[0].amount.Equal(math.NewInt(5000)) {
+		t.Errorf("first target should be granted with 5000 but is %s %s", targets[0].granter, targets[0].amount)
+	}
+	if !targets[1].granter.Equals(capped) || !targets[1].amount.Equal(math.NewInt(2000)) {
+		t.Errorf("second target should be capped with the max tokens 2000 but is %s %s", targets[1].granter, targets[1].amount)
+	}
+}
+
+func TestRestakeRunSendsExec(t *testing.T) {
+	granter := sdk.AccAddress([]byte("granter_____________"))
+	chain := &fakeChain{}
+	chain.grantRestake(granter, nil, testValidator)
+	chain.reward(granter, 1500)
+
+	sut := newTestRestaker(chain)
+	err := sut.run(context.Background())
+
+	if err != nil {
+		t.Fatalf("run error %v", err)
+	}
+	if len(chain.sent) != 1 || len(chain.sent[0]) != 1 {
+		t.Fatalf("run should send one transaction with one message but sent %v", chain.sent)
+	}
+	exec, ok := chain.sent[0][0].(*authz.MsgExec)
+	if !ok {
+		t.Fatalf("the message should be a MsgExec but is %T", chain.sent[0][0])
+	}
+	if exec.Grantee != testGrantee.String() {
+		t.Errorf("the MsgExec grantee should be %s but is %s", testGrantee, exec.Grantee)
+	}
+	msgs, err := exec.GetMessages()
+	if err != nil {
+		t.Fatalf("GetMessages error %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("the MsgExec should withdraw and delegate but has %v", msgs)
+	}
+	withdraw, ok := msgs[0].(*distrtypes.MsgWithdrawDelegatorReward)
+	if !ok || withdraw.DelegatorAddress != granter.String() || withdraw.ValidatorAddress != testValidator.String() {
+		t.Errorf("the first message should withdraw the rewards of the granter but is %v", msgs[0])
+	}
+	delegate, ok := msgs[1].(*stakingtypes.MsgDelegate)
+	if !ok || delegate.DelegatorAddress != granter.String() || !delegate.Amount.IsEqual(sdk.NewInt64Coin("uatom", 1500)) {
+		t.Errorf("the second message should delegate 1500uatom of the granter but is %v", msgs[1])
+	}
+}
+
+func TestRestakeRunContinuesAfterFailure(t *testing.T) {
+	first := sdk.AccAddress([]byte("first_______________"))
+	second := sdk.AccAddress([]byte("second______________"))
+	chain := &fakeChain{sendErr: errors.New("broadcast failed")}
+	chain.grantRestake(first, nil, testValidator)
+	chain.reward(first, 5000)
+	chain.grantRestake(second, nil, testValidator)
+	chain.reward(second, 5000)
+
+	sut := newTestRestaker(chain)
+	err := sut.run(context.Background())
+
+	if err == nil {
+		t.Errorf("run should return an error when a restake fails")
+	}
+	if len(chain.sent) != 2 {
+		t.Errorf("run should try to restake both granters but sent %d transactions", len(chain.sent))
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// maxSequenceRetries limits the resyncs of a transaction rejected by an account sequence mismatch
+const maxSequenceRetries = 3
+
+// sequenceRetryDelay is multiplied by the attempt before a retry, the first retry is immediate
+var sequenceRetryDelay = time.Second
+
+// ie: "account sequence mismatch, expected 12, got 11: incorrect account sequence"
+var sequenceMismatch = regexp.MustCompile(`account sequence mismatch, expected (\d+)`)
+
+// Local account number and sequence of an account. The sequence is incremented once a transaction passes CheckTx
+// without waiting for the block, so the transactions of a burst do not re-query the account and collide.
+// https://docs.cosmos.network/v0.46/basics/accounts.html
+type sequenceTracker struct {
+	mu            sync.Mutex
+	c             *client.Client
+	address       sdk.AccAddress
+	accountNumber uint64
+	sequence      uint64
+	synced        bool
+}
+
+func newSequenceTracker(c *client.Client, address sdk.AccAddress) *sequenceTracker {
+	return &sequenceTracker{c: c, address: address}
+}
+
+// The account number and the next sequence, the account is queried on the first call and after a resync. The
+// queried sequence is the one of the last block, so it is lower than the local one while there are transactions
+// of the account in the mempool: the highest one is kept.
+func (t *sequenceTracker) next() (uint64, uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.synced {
+		acc, err := t.c.GetAccount(context.Background(), t.address)
+		if err != nil {
+			return 0, 0, err
+		}
+		t.accountNumber, t.synced = acc.GetAccountNumber(), true
+		if acc.GetSequence() > t.sequence {
+			t.sequence = acc.GetSequence()
+		}
+		fmt.Fprintln(os.Stderr, "Synced account number", t.accountNumber, "sequence", t.sequence)
+	}
+	return t.accountNumber, t.sequence, nil
+}
+
+// The transaction with sequence was accepted by the node
+func (t *sequenceTracker) increment(sequence uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sequence == sequence {
+		t.sequence++
+	}
+}
+
+// The local sequence is wrong, the next call queries the account. expected is the sequence reported by the node,
+// 0 when it is unknown.
+func (t *sequenceTracker) resync(expected uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sequence, t.synced = expected, false
+}
+
+// A transaction to send and the channel where its result is delivered
+type sendRequest struct {
+	memo   string
+	msgs   []sdk.Msg
+	result chan sendResult
+}
+
+type sendResult struct {
+	txRes *sdk.TxResponse
+	err   error
+}
+
+// Queue of the transactions of one account. It can be used from several goroutines, a single worker signs and
+// broadcasts the transactions in order so each one gets the next sequence. A transaction rejected with an account
+// sequence mismatch is resynced and retried.
+type senderQueue struct {
+	c         *client.Client
+	net       network
+	from      account
+	dryRun    bool
+	sequences *sequenceTracker
+	requests  chan sendRequest
+	done      chan struct{}
+}
+
+// Start the worker of the queue, size is the number of transactions that can be queued without blocking
+func newSenderQueue(c *client.Client, net network, from account, dryRun bool, size int) *senderQueue {
+	q := &senderQueue{
+		c:         c,
+		net:       net,
+		from:      from,
+		dryRun:    dryRun,
+		sequences: newSequenceTracker(c, from.address),
+		requests:  make(chan sendRequest, size),
+		done:      make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Queue the messages as a transaction, the result is delivered once it is broadcasted(nil on dry run)
+func (q *senderQueue) submit(memo string, msgs ...sdk.Msg) <-chan sendResult {
+	result := make(chan sendResult, 1)
+	q.requests <- sendRequest{memo: memo, msgs: msgs, result: result}
+	return result
+}
+
+// Queue the messages and wait until the transaction is broadcasted
+func (q *senderQueue) send(memo string, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	res := <-q.submit(memo, msgs...)
+	return res.txRes, res.err
+}
+
+// Stop the worker once the queued transactions are sent, submit must not be called after close
+func (q *senderQueue) close() {
+	close(q.requests)
+	<-q.done
+}
+
+func (q *senderQueue) run() {
+	defer close(q.done)
+	for req := range q.requests {
+		txRes, err := q.broadcast(req.memo, req.msgs...)
+		req.result <- sendResult{txRes: txRes, err: err}
+	}
+}
+
+func (q *senderQueue) broadcast(memo string, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	for attempt := 0; ; attempt++ {
+		accountNumber, sequence, err := q.sequences.next()
+		if err != nil {
+			return nil, err
+		}
+		txRes, err := sendMessages(q.c, q.net, q.from, accountNumber, sequence, memo, q.dryRun, msgs...)
+		expected, mismatch := isSequenceMismatch(txRes, err)
+		if mismatch && attempt < maxSequenceRetries {
+			fmt.Fprintln(os.Stderr, "Account sequence mismatch with sequence", sequence, "resyncing, attempt", attempt+1)
+			q.sequences.resync(expected)
+			time.Sleep(time.Duration(attempt) * sequenceRetryDelay)
+			continue
+		}
+		if err == nil && txRes != nil && txRes.Code == 0 {
+			q.sequences.increment(sequence)
+		}
+		return txRes, err
+	}
+}
+
+// A sequence mismatch is detected by the simulation(an error) or by CheckTx(the code of the response), both are
+// client.ErrSequenceMismatch. The expected sequence of the log is returned when it is present, 0 otherwise.
+func isSequenceMismatch(txRes *sdk.TxResponse, err error) (uint64, bool) {
+	if err == nil {
+		err = client.CheckTxResponse(txRes)
+	}
+	if !errors.Is(err, client.ErrSequenceMismatch) {
+		return 0, false
+	}
+	var expected uint64
+	if match := sequenceMismatch.FindStringSubmatch(err.Error()); match != nil {
+		expected, _ = strconv.ParseUint(match[1], 10, 64)
+	}
+	return expected, true
+}
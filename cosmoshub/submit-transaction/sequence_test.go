@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// the sequences signed in the transactions accepted by the node, in broadcast order
+func signedSequences(t *testing.T, txs []sdk.Tx) []uint64 {
+	var sequences []uint64
+	for _, tx := range txs {
+		sigs, err := tx.(authsigning.SigVerifiableTx).GetSignaturesV2()
+		if err != nil {
+			t.Fatalf("GetSignaturesV2 error %v", err)
+		}
+		sequences = append(sequences, sigs[0].Sequence)
+	}
+	return sequences
+}
+
+func fastSequenceRetries(t *testing.T) {
+	delay := sequenceRetryDelay
+	sequenceRetryDelay = time.Millisecond
+	t.Cleanup(func() { sequenceRetryDelay = delay })
+}
+
+func TestSenderQueueResyncs(t *testing.T) {
+	fastSequenceRetries(t)
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	sut := newSenderQueue(c, testNetwork, from, false, 1)
+	defer sut.close()
+	msg := newMsgSend(from.address, from.address, sdk.NewInt64Coin("uatom", 1))
+
+	first, err := sut.send("first", msg)
+	if err != nil || first.Code != 0 {
+		t.Fatalf("the first transaction should be accepted but is %v, %v", first, err)
+	}
+	// transactions of the account sent by another program, the local sequence 1 is behind
+	node.SetSequence(from.address, 5)
+	second, err := sut.send("second", msg)
+
+	if err != nil || second.Code != 0 {
+		t.Fatalf("the second transaction should be accepted after the resync but is %v, %v", second, err)
+	}
+	if sequences := signedSequences(t, node.Txs()); len(sequences) != 2 || sequences[0] != 0 || sequences[1] != 5 {
+		t.Errorf("the transactions should be signed with the sequences 0 and 5 but are %v", sequences)
+	}
+	if node.Sequence(from.address) != 6 {
+		t.Errorf("the sequence of the account should be 6 but is %d", node.Sequence(from.address))
+	}
+}
+
+func TestSenderQueueStopsRetrying(t *testing.T) {
+	fastSequenceRetries(t)
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	for i := 0; i < maxSequenceRetries+1; i++ {
+		node.RejectNextTx(32, "sdk", "account sequence mismatch, expected 0, got 0: incorrect account sequence")
+	}
+	sut := newSenderQueue(c, testNetwork, from, false, 1)
+	defer sut.close()
+	msg := newMsgSend(from.address, from.address, sdk.NewInt64Coin("uatom", 1))
+
+	rejected, err := sut.send("rejected", msg)
+	if err != nil {
+		t.Fatalf("send error %v", err)
+	}
+	accepted, err := sut.send("accepted", msg)
+
+	if err := client.CheckTxResponse(rejected); !errors.Is(err, client.ErrSequenceMismatch) {
+		t.Errorf("the transaction should be rejected after %d retries but is %v", maxSequenceRetries, err)
+	}
+	if err != nil || accepted.Code != 0 {
+		t.Errorf("the next transaction should be sent once the rejections are consumed but is %v, %v", accepted, err)
+	}
+	if sequences := signedSequences(t, node.Txs()); len(sequences) != 1 || sequences[0] != 0 {
+		t.Errorf("only the next transaction should be accepted with the sequence 0 but the sequences are %v", sequences)
+	}
+}
+
+func TestSenderQueueConcurrentSends(t *testing.T) {
+	node, c := startNode(t)
+	from := newTestAccount()
+	node.AddAccount(from.address, sdk.NewInt64Coin("uatom", 1000000))
+	sut := newSenderQueue(c, testNetwork, from, false, 4)
+	defer sut.close()
+	msg := newMsgSend(from.address, from.address, sdk.NewInt64Coin("uatom", 1))
+
+	const senders = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, senders)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txRes, err := sut.send("concurrent", msg)
+			if err == nil {
+				err = client.CheckTxResponse(txRes)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("the concurrent transactions should be accepted but one fails %v", err)
+		}
+	}
+	sequences := signedSequences(t, node.Txs())
+	if len(sequences) != senders {
+		t.Fatalf("the node should accept %d transactions but accepts %d", senders, len(sequences))
+	}
+	for i, sequence := range sequences {
+		if sequence != uint64(i) {
+			t.Errorf("the transactions should have consecutive sequences but are %v", sequences)
+			break
+		}
+	}
+	if node.Sequence(from.address) != senders {
+		t.Errorf("the sequence of the account should be %d but is %d", senders, node.Sequence(from.address))
+	}
+}
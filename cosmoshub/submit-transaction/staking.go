@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const stakingUsage = `usage: submit-transaction staking <command> [flags]
+
+transactions, signed by the -from key:
+  delegate          delegate -amount to -validator
+  undelegate        undelegate -amount from -validator, the coins are unbonded after the unbonding time(21 days on the cosmoshub)
+  redelegate        move -amount from -validator to -dst-validator without unbonding
+  withdraw-rewards  withdraw the rewards of -validator, or of all the delegations when it is empty
+
+queries, for the -from key or address:
+  delegations       delegations and their balance
+  unbonding         unbonding entries and their completion time
+  rewards           pending rewards of each validator and their total
+
+the amount is in the network denom(10000) or a display denom(0.01atom), it must be the bond denom of the chain(uatom
+on the cosmoshub)`
+
+var stakingCommands = map[string]func(args []string) error{
+	"delegate":         runDelegate,
+	"undelegate":       runUndelegate,
+	"redelegate":       runRedelegate,
+	"withdraw-rewards": runWithdrawRewards,
+	"delegations":      runDelegations,
+	"unbonding":        runUnbonding,
+	"rewards":          runRewards,
+}
+
+// staking <command> [flags]
+func runStaking(args []string) error {
+	if len(args) == 0 {
+		return errors.New(stakingUsage)
+	}
+	command, ok := stakingCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("staking: unknown command %q\n%s", args[0], stakingUsage)
+	}
+	return command(args[1:])
+}
+
+// Flags of the staking transactions
+type stakingParams struct {
+	params
+	validator    string
+	dstValidator string
+	wait         bool
+}
+
+func newStakingFlagSet(name string, p *stakingParams) *flag.FlagSet {
+	flags := newFlagSet(name, &p.params)
+	flags.StringVar(&p.validator, "validator", "", "bech32 operator address of the validator, ie: cosmosvaloper1...")
+	flags.StringVar(&p.dstValidator, "dst-validator", "", "bech32 operator address of the destination validator of a redelegation")
+	flags.BoolVar(&p.wait, "wait", false, "wait until the transaction is included in a block")
+	return flags
+}
+
+// staking delegate -validator <address> -amount n [flags]
+func runDelegate(args []string) error {
+	return runStakingTx("staking delegate", args, func(p stakingParams, net network, c *client.Client, from account) ([]sdk.Msg, error) {
+		validator, err := net.Bech32.ParseValAddress(p.validator)
+		if err != nil {
+			return nil, fmt.Errorf("-validator: %w", err)
+		}
+		amount, err := parseAmount(net, p.amount)
+		if err != nil {
+			return nil, fmt.Errorf("-amount: %w", err)
+		}
+		// https://docs.cosmos.network/v0.46/modules/staking/03_messages.html#msgdelegate
+		return []sdk.Msg{stakingtypes.NewMsgDelegate(from.address, validator, amount)}, nil
+	})
+}
+
+// staking undelegate -validator <address> -amount n [flags]
+func runUndelegate(args []string) error {
+	return runStakingTx("staking undelegate", args, func(p stakingParams, net network, c *client.Client, from account) ([]sdk.Msg, error) {
+		validator, err := net.Bech32.ParseValAddress(p.validator)
+		if err != nil {
+			return nil, fmt.Errorf("-validator: %w", err)
+		}
+		amount, err := parseAmount(net, p.amount)
+		if err != nil {
+			return nil, fmt.Errorf("-amount: %w", err)
+		}
+		// https://docs.cosmos.network/v0.46/modules/staking/03_messages.html#msgundelegate
+		return []sdk.Msg{stakingtypes.NewMsgUndelegate(from.address, validator, amount)}, nil
+	})
+}
+
+// staking redelegate -validator <address> -dst-validator <address> -amount n [flags]
+func runRedelegate(args []string) error {
+	return runStakingTx("staking redelegate", args, func(p stakingParams, net network, c *client.Client, from account) ([]sdk.Msg, error) {
+		src, err := net.Bech32.ParseValAddress(p.validator)
+		if err != nil {
+			return nil, fmt.Errorf("-validator: %w", err)
+		}
+		dst, err := net.Bech32.ParseValAddress(p.dstValidator)
+		if err != nil {
+			return nil, fmt.Errorf("-dst-validator: %w", err)
+		}
+		amount, err := parseAmount(net, p.amount)
+		if err != nil {
+			return nil, fmt.Errorf("-amount: %w", err)
+		}
+		// https://docs.cosmos.network/v0.46/modules/staking/03_messages.html#msgbeginredelegate
+		return []sdk.Msg{stakingtypes.NewMsgBeginRedelegate(from.address, src, dst, amount)}, nil
+	})
+}
+
+// staking withdraw-rewards [-validator <address>] [flags], a message per delegation when the validator is empty
+func runWithdrawRewards(args []string) error {
+	return runStakingTx("staking withdraw-rewards", args, func(p stakingParams, net network, c *client.Client, from account) ([]sdk.Msg, error) {
+		var validators []sdk.ValAddress
+		if p.validator != "" {
+			validator, err := net.Bech32.ParseValAddress(p.validator)
+			if err != nil {
+				return nil, fmt.Errorf("-validator: %w", err)
+			}
+			validators = append(validators, validator)
+		} else {
+			delegations, err := c.Delegations(context.Background(), from.address)
+			if err != nil {
+				return nil, err
+			}
+			for _, delegation := range delegations {
+				validator, err := net.Bech32.ParseValAddress(delegation.Delegation.ValidatorAddress)
+				if err != nil {
+					return nil, err
+				}
+				validators = append(validators, validator)
+			}
+		}
+		if len(validators) == 0 {
+			return nil, fmt.Errorf("%s has no delegations", from.bech32())
+		}
+		// https://docs.cosmos.network/v0.46/modules/distribution/04_messages.html#msgwithdrawdelegatorreward
+		msgs := make([]sdk.Msg, 0, len(validators))
+		for _, validator := range validators {
+			msgs = append(msgs, distrtypes.NewMsgWithdrawDelegatorReward(from.address, validator))
+		}
+		return msgs, nil
+	})
+}
+
+// Parse the flags, load the signer, build the messages with newMsgs and send them in a transaction
+func runStakingTx(name string, args []string, newMsgs func(p stakingParams, net network, c *client.Client, from account) ([]sdk.Msg, error)) error {
+	var p stakingParams
+	flags := newStakingFlagSet(name, &p)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p.params, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	msgs, err := newMsgs(p, net, c, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := sendAndWait(c, net, from, p.dryRun, p.wait, msgs...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// staking delegations [flags]
+func runDelegations(args []string) error {
+	return runStakingQuery("staking delegations", args, func(c *client.Client, delegator sdk.AccAddress, page []client.PageOption) error {
+		delegations, err := c.Delegations(context.Background(), delegator, page...)
+		if err != nil {
+			return err
+		}
+		for _, delegation := range delegations {
+			fmt.Printf("%s\t%s\tshares %s\n", delegation.Delegation.ValidatorAddress, delegation.Balance.String(), delegation.Delegation.Shares.String())
+		}
+		return nil
+	})
+}
+
+// staking unbonding [flags]
+func runUnbonding(args []string) error {
+	return runStakingQuery("staking unbonding", args, func(c *client.Client, delegator sdk.AccAddress, page []client.PageOption) error {
+		unbondings, err := c.UnbondingDelegations(context.Background(), delegator, page...)
+		if err != nil {
+			return err
+		}
+		for _, unbonding := range unbondings {
+			for _, entry := range unbonding.Entries {
+				fmt.Printf("%s\t%s\theight %d\tcompletes %s\n", unbonding.ValidatorAddress, entry.Balance.String(), entry.CreationHeight, entry.CompletionTime.Format("2006-01-02 15:04:05 MST"))
+			}
+		}
+		return nil
+	})
+}
+
+// staking rewards [flags]
+func runRewards(args []string) error {
+	return runStakingQuery("staking rewards", args, func(c *client.Client, delegator sdk.AccAddress, page []client.PageOption) error {
+		rewards, err := c.Rewards(context.Background(), delegator)
+		if err != nil {
+			return err
+		}
+		for _, reward := range rewards.Rewards {
+			fmt.Printf("%s\t%s\n", reward.ValidatorAddress, reward.Reward.String())
+		}
+		fmt.Printf("total\t%s\n", rewards.Total.String())
+		return nil
+	})
+}
+
+// Parse the flags and run query for the -from key or address, the page flags limit the lists
+func runStakingQuery(name string, args []string, query func(c *client.Client, delegator sdk.AccAddress, page []client.PageOption) error) error {
+	var p params
+	flags := newFlagSet(name, &p)
+	page := newPageFlags(flags)
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	delegator, err := resolveAddress(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	fmt.Println("Delegator", net.Bech32.AccAddress(delegator))
+	if err := query(c, delegator, page.options()); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"cosmoshub/client"
+
+	"cosmossdk.io/math"
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// Create a transaction with the messages, estimate its gas and fee, sign it and broadcast it. On dry run only the
+// estimation is printed and nil is returned.
+func sendMessages(c *client.Client, net network, from account, accountNumber uint64, sequence uint64, memo string, dryRun bool, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	// create the transaction
+	txConfig := client.NewTxConfig() // https://docs.cosmos.network/v0.46/core/transactions.html#transaction-generation
+	feeGranter, err := net.feeGranter()
+	if err != nil {
+		return nil, err
+	}
+	txBuilder, err := createTransaction(txConfig, feeGranter, msgs...)
+	if err != nil {
+		return nil, err
+	}
+	txBuilder.SetMemo(memo)
+	if err := setSignerInfo(txConfig, txBuilder, from, sequence); err != nil {
+		return nil, err
+	}
+
+	// estimate gas and fees
+	gasLimit, fee, err := estimateFee(c, net, txConfig, txBuilder)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "Estimated gas limit", gasLimit, "fee", fee.String())
+	if dryRun {
+		return nil, nil
+	}
+	txBuilder.SetGasLimit(gasLimit)           // max units of gas
+	txBuilder.SetFeeAmount(sdk.NewCoins(fee)) // the maximum amount the user is willing to pay in fees.
+
+	// sign the transaction
+	txBytes, err := signTransaction(txConfig, txBuilder, net.ChainID, from, accountNumber, sequence)
+	if err != nil {
+		return nil, err
+	}
+	if err := printTx(txConfig, txBuilder.GetTx()); err != nil {
+		return nil, err
+	}
+
+	// broadcast transaction
+	return broadcastTransaction(c, txBytes)
+}
+
+// Send the messages in a transaction with the account number and sequence of the chain. A rejected transaction is a
+// *client.TxError and, with wait, the transaction is waited until it is included in a block.
+func sendAndWait(c *client.Client, net network, from account, dryRun bool, wait bool, msgs ...sdk.Msg) error {
+	acc, err := getAccount(c, from.address)
+	if err != nil {
+		return err
+	}
+	txRes, err := sendMessages(c, net, from, acc.GetAccountNumber(), acc.GetSequence(), "", dryRun, msgs...)
+	if err != nil || dryRun {
+		return err
+	}
+	if err := client.CheckTxResponse(txRes); err != nil {
+		return err
+	}
+	if !wait {
+		return nil
+	}
+	_, err = waitForTransaction(c, net, txRes)
+	return err
+}
+
+// Create the transaction builder with the messages, the fees are paid by feeGranter when it is not nil
+func createTransaction(txConfig sdkclient.TxConfig, feeGranter sdk.AccAddress, msgs ...sdk.Msg) (sdkclient.TxBuilder, error) {
+	txBuilder := txConfig.NewTxBuilder() // https://pkg.go.dev/github.com/cosmos/cosmos-sdk@v0.46.0/client#TxConfig
+	err := txBuilder.SetMsgs(msgs...)    // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#Msg
+	if err != nil {
+		return nil, fmt.Errorf("set msgs: %w", err)
+	}
+	// the granter must have given a feegrant allowance to the signer
+	// https://docs.cosmos.network/v0.46/modules/feegrant/01_concepts.html#fee-allowances
+	txBuilder.SetFeeGranter(feeGranter)
+	return txBuilder, nil
+}
+
+// First round of the signature: gather all the signer infos. We use the "set empty signature" hack to do that.
+// The simulation only needs the signer infos, so it can run before signing.
+// main info https://docs.cosmos.network/master/run-node/txs.html
+// accounts https://docs.cosmos.network/master/basics/accounts.html
+// https://docs.cosmos.network/v0.46/modules/auth/02_state.html
+func setSignerInfo(txConfig sdkclient.TxConfig, txBuilder sdkclient.TxBuilder, from account, sequence uint64) error {
+	sigV2 := signing.SignatureV2{
+		PubKey:   from.pubKey,
+		Data:     emptySignatureData(txConfig, from.pubKey),
+		Sequence: sequence,
+	}
+	if err := txBuilder.SetSignatures(sigV2); err != nil {
+		return fmt.Errorf("set signer info: %w", err)
+	}
+	return nil
+}
+
+// A multisig needs a signature of each of the threshold members to simulate the gas of the signature verification
+func emptySignatureData(txConfig sdkclient.TxConfig, pubKey cryptotypes.PubKey) signing.SignatureData {
+	multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return &signing.SingleSignatureData{SignMode: txConfig.SignModeHandler().DefaultMode()}
+	}
+	data := multisig.NewMultisig(len(multisigPubKey.PubKeys)) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/crypto/types/multisig#NewMultisig
+	for i := 0; i < int(multisigPubKey.Threshold); i++ {
+		data.BitArray.SetIndex(i, true)
+		data.Signatures = append(data.Signatures, &signing.SingleSignatureData{SignMode: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON})
+	}
+	return data
+}
+
+// Estimate the gas limit and the fee of the transaction
+// https://docs.cosmos.network/master/basics/gas-fees.html
+// https://docs.cosmos.network/master/basics/tx-lifecycle.html
+//   - gas limit: the profile gas limit or, when it is 0, the simulated gas used multiplied by the gas adjustment
+//   - fee: the profile fee or, when it is 0, gas limit * gas price. The gas price of the profile is required then
+func estimateFee(c *client.Client, net network, txConfig sdkclient.TxConfig, txBuilder sdkclient.TxBuilder) (uint64, sdk.Coin, error) {
+	gasLimit := net.GasLimit
+	if gasLimit == 0 {
+		txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+		if err != nil {
+			return 0, sdk.Coin{}, fmt.Errorf("encode tx: %w", err)
+		}
+		gasInfo, err := c.Simulate(context.Background(), txBytes)
+		if err != nil {
+			return 0, sdk.Coin{}, err
+		}
+		fmt.Fprintln(os.Stderr, "Simulated gas used", gasInfo.GasUsed, "adjustment", net.GasAdjustment)
+		gasLimit = client.AdjustGas(gasInfo.GasUsed, net.GasAdjustment)
+	}
+	if net.Fee > 0 {
+		return gasLimit, sdk.NewCoin(net.Denom, math.NewInt(net.Fee)), nil
+	}
+	gasPrice, err := resolveGasPrice(net)
+	if err != nil {
+		return 0, sdk.Coin{}, err
+	}
+	fmt.Fprintln(os.Stderr, "Gas price", gasPrice.String())
+	return gasLimit, client.ComputeFee(gasLimit, gasPrice), nil
+}
+
+// The gas price of the profile, it must be at least the minimum-gas-prices of the node app.toml
+// https://docs.cosmos.network/v0.46/basics/gas-fees.html#introduction-to-gas-and-fees
+func resolveGasPrice(net network) (sdk.DecCoin, error) {
+	if net.GasPrice == "" {
+		return sdk.DecCoin{}, errors.New("the profile has no gas-price, set gas-price(-gas-price) or fee(-fee)")
+	}
+	gasPrice, err := sdk.ParseDecCoin(net.GasPrice) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/types#ParseDecCoin
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("gas price %s: %w", net.GasPrice, err)
+	}
+	return gasPrice, nil
+}
+
+// Second round of the signature: all signer infos are set, so each signer can sign. Returns the transaction bytes.
+func signTransaction(txConfig sdkclient.TxConfig, txBuilder sdkclient.TxBuilder, chainID string, from account, accountNumber uint64, sequence uint64) ([]byte, error) {
+	signerData := xauthsigning.SignerData{ // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/x/auth/signing
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+	}
+	sigV2, err := clienttx.SignWithPrivKey( // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/client/tx#SignWithPrivKey
+		txConfig.SignModeHandler().DefaultMode(),
+		signerData,
+		txBuilder,
+		from.privKey,
+		txConfig,
+		sequence)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	err = txBuilder.SetSignatures(sigV2)
+	if err != nil {
+		return nil, fmt.Errorf("set signatures: %w", err)
+	}
+
+	// generate transaction
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("encode tx: %w", err)
+	}
+	return txBytes, nil
+}
+
+// Print the signed transaction as JSON and as base64 bytes, the format used by explorers and the tendermint RPC
+func printTx(txConfig sdkclient.TxConfig, tx sdk.Tx) error {
+	for _, encoding := range []string{client.EncodingJSON, client.EncodingBase64} {
+		encoded, err := client.EncodeTx(txConfig, tx, encoding)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Transaction", encoding, encoded)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const txUsage = `usage: submit-transaction tx <command> [flags] [file]
+
+commands:
+  build      build an unsigned transfer with -generate-only, the JSON transaction is written to -out or stdout
+  sign       sign a JSON transaction file with the -from key. With -offline the node is not queried and
+             -account-number and -sequence are required
+  broadcast  broadcast a signed transaction file
+  decode     print the JSON of a transaction encoded as base64(ie: from an explorer or the tendermint RPC) or hex,
+             the argument is the encoded transaction or - to read it from stdin
+
+the files are JSON by default, build and sign can write them as base64 or hex with -encoding
+
+offline signing:
+  online:     submit-transaction tx build -generate-only -from <key or address> -to <address> -out unsigned.json
+  air-gapped: submit-transaction tx sign -offline -account-number <n> -sequence <n> -from <key> -out signed.json unsigned.json
+  online:     submit-transaction tx broadcast signed.json`
+
+var txCommands = map[string]func(args []string) error{
+	"build":     runTxBuild,
+	"sign":      runTxSign,
+	"broadcast": runTxBroadcast,
+	"decode":    runTxDecode,
+}
+
+// tx <command> [flags] [file]
+func runTx(args []string) error {
+	if len(args) == 0 {
+		return errors.New(txUsage)
+	}
+	command, ok := txCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("tx: unknown command %q\n%s", args[0], txUsage)
+	}
+	return command(args[1:])
+}
+
+// tx build -generate-only [flags]: the account is queried to print the account number and sequence needed to sign
+// offline and the gas is simulated when the public key of the sender is known
+func runTxBuild(args []string) error {
+	var p params
+	flags := newFlagSet("tx build", &p)
+	generateOnly := flags.Bool("generate-only", false, "build the unsigned transaction without signing nor broadcasting it")
+	out := flags.String("out", "", "file where the JSON transaction is written, stdout when empty")
+	encoding := flags.String("encoding", client.EncodingJSON, "encoding of the written transaction: json, base64 or hex")
+	multisigFile := flags.String("multisig", "", "multisig public key file of the sender, created with multisig create. -from is ignored")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if !*generateOnly {
+		return errors.New("tx build: only -generate-only is supported, run without command to build, sign and broadcast")
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	var from account
+	if *multisigFile != "" {
+		from, err = loadMultisig(*multisigFile, net.Bech32)
+	} else {
+		from, err = resolveSigner(kr, p.from, net.Bech32)
+	}
+	if err != nil {
+		return err
+	}
+	to, err := resolveAddress(kr, p.to, net.Bech32)
+	if err != nil {
+		return err
+	}
+
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	acc, err := getAccount(c, from.address)
+	if err != nil {
+		return err
+	}
+	if from.pubKey == nil {
+		// the public key is on chain once the account has sent a transaction
+		from.pubKey = acc.GetPubKey()
+	}
+
+	amount, err := parseAmount(net, p.amount)
+	if err != nil {
+		return fmt.Errorf("tx build: -amount: %w", err)
+	}
+	feeGranter, err := net.feeGranter()
+	if err != nil {
+		return err
+	}
+	txConfig := client.NewTxConfig()
+	txBuilder, err := createTransaction(txConfig, feeGranter, newMsgSend(from.address, to, amount))
+	if err != nil {
+		return err
+	}
+	if net.GasLimit == 0 {
+		// the simulation needs the signer infos
+		if from.pubKey == nil {
+			return fmt.Errorf("tx build: unknown public key of %s, set -gas to skip the simulation", from.bech32())
+		}
+		if err := setSignerInfo(txConfig, txBuilder, from, acc.GetSequence()); err != nil {
+			return err
+		}
+	}
+	gasLimit, fee, err := estimateFee(c, net, txConfig, txBuilder)
+	if err != nil {
+		return err
+	}
+	txBuilder.SetGasLimit(gasLimit)
+	txBuilder.SetFeeAmount(sdk.NewCoins(fee))
+
+	// the signer infos are set again when signing
+	if err := txBuilder.SetSignatures(); err != nil {
+		return err
+	}
+	signCommand := "tx sign"
+	if *multisigFile != "" {
+		signCommand = "multisig sign -multisig " + *multisigFile
+	}
+	fmt.Fprintf(os.Stderr, "Sign with: submit-transaction %s -offline -chain-id %s -account-number %d -sequence %d -from <key> <file>\n",
+		signCommand, net.ChainID, acc.GetAccountNumber(), acc.GetSequence())
+	return writeTx(txConfig, txBuilder.GetTx(), *out, *encoding)
+}
+
+// tx sign [-offline -account-number n -sequence n] [flags] <file>
+func runTxSign(args []string) error {
+	var p params
+	flags := newFlagSet("tx sign", &p)
+	signer := signerFlags(flags)
+	out := flags.String("out", "", "file where the signed JSON transaction is written, stdout when empty")
+	encoding := flags.String("encoding", client.EncodingJSON, "encoding of the written transaction: json, base64 or hex")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	file := flags.Arg(0)
+	if file == "" {
+		return errors.New(txUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	txConfig := client.NewTxConfig()
+	txBuilder, err := readTx(txConfig, file)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	from, err := loadAccount(kr, p.from, net.Bech32)
+	if err != nil {
+		return err
+	}
+	if !isSigner(txBuilder.GetTx(), from.address) {
+		return fmt.Errorf("tx sign: %s is not a signer of the transaction", from.bech32())
+	}
+	accountNumber, sequence, err := signer.resolve(flags, net, from.address)
+	if err != nil {
+		return err
+	}
+	if err := setSignerInfo(txConfig, txBuilder, from, sequence); err != nil {
+		return err
+	}
+	if _, err := signTransaction(txConfig, txBuilder, net.ChainID, from, accountNumber, sequence); err != nil {
+		return err
+	}
+	return writeTx(txConfig, txBuilder.GetTx(), *out, *encoding)
+}
+
+// tx broadcast [-wait] [flags] <file>
+func runTxBroadcast(args []string) error {
+	var p params
+	flags := newFlagSet("tx broadcast", &p)
+	wait := flags.Bool("wait", false, "wait until the transaction is included in a block")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	file := flags.Arg(0)
+	if file == "" {
+		return errors.New(txUsage)
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	net.Bech32.SetGlobal()
+
+	txConfig := client.NewTxConfig()
+	txBuilder, err := readTx(txConfig, file)
+	if err != nil {
+		return err
+	}
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", file, err)
+	}
+
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	txRes, err := broadcastTransaction(c, txBytes)
+	if err != nil {
+		return err
+	}
+	if err := client.CheckTxResponse(txRes); err != nil {
+		return fmt.Errorf("tx broadcast: %w", err)
+	}
+	if !*wait {
+		return nil
+	}
+	_, err = waitForTransaction(c, net, txRes)
+	return err
+}
+
+// tx decode [-encoding base64|hex] <encoded tx or ->
+func runTxDecode(args []string) error {
+	flags := flag.NewFlagSet("tx decode", flag.ContinueOnError)
+	encoding := flags.String("encoding", "", "encoding of the transaction: base64, hex or json, detected when empty")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	encoded := flags.Arg(0)
+	if encoded == "" {
+		return errors.New(txUsage)
+	}
+	if encoded == "-" {
+		bz, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		encoded = string(bz)
+	}
+
+	txConfig := client.NewTxConfig()
+	tx, err := client.DecodeTx(txConfig, encoded, *encoding)
+	if err != nil {
+		return err
+	}
+	decoded, err := client.EncodeTx(txConfig, tx, client.EncodingJSON)
+	if err != nil {
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(decoded), "", "  "); err != nil {
+		return err
+	}
+	fmt.Println(indented.String())
+	return nil
+}
+
+// The sender of an unsigned transaction is a key of the keyring, only its public key is read, or a bech32 address
+func resolveSigner(kr *keyring.Keyring, nameOrAddress string, prefixes client.Bech32Prefixes) (account, error) {
+	if address, err := prefixes.ParseAccAddress(nameOrAddress); err == nil {
+		return account{address: address, prefixes: prefixes}, nil
+	}
+	info, err := kr.Show(nameOrAddress)
+	if err != nil {
+		return account{}, err
+	}
+	return account{pubKey: info.PubKey, address: info.Address, prefixes: prefixes}, nil
+}
+
+func isSigner(tx sdk.Tx, address sdk.AccAddress) bool {
+	for _, msg := range tx.GetMsgs() {
+		for _, signer := range msg.GetSigners() {
+			if signer.Equals(address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Account number and sequence flags shared by the commands that sign
+type offlineSigner struct {
+	offline       *bool
+	accountNumber *uint64
+	sequence      *uint64
+}
+
+func signerFlags(flags *flag.FlagSet) offlineSigner {
+	return offlineSigner{
+		offline:       flags.Bool("offline", false, "sign without querying the node, -account-number and -sequence are required"),
+		accountNumber: flags.Uint64("account-number", 0, "account number of the signer, queried when not -offline"),
+		sequence:      flags.Uint64("sequence", 0, "sequence of the signer, queried when not -offline"),
+	}
+}
+
+// The account number and sequence of the flags when offline, otherwise they are queried
+func (s offlineSigner) resolve(flags *flag.FlagSet, net network, address sdk.AccAddress) (uint64, uint64, error) {
+	accountNumber, sequence := *s.accountNumber, *s.sequence
+	if *s.offline {
+		if !isFlagSet(flags, "account-number") || !isFlagSet(flags, "sequence") {
+			return 0, 0, errors.New("-offline requires -account-number and -sequence")
+		}
+	} else {
+		c, err := createClient(net)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer c.Close()
+		acc, err := getAccount(c, address)
+		if err != nil {
+			return 0, 0, err
+		}
+		accountNumber, sequence = acc.GetAccountNumber(), acc.GetSequence()
+	}
+	// the chain id, account number and sequence are part of the signed bytes, a wrong value is only detected on broadcast
+	fmt.Fprintln(os.Stderr, "Signing for chain-id", net.ChainID, "account number", accountNumber, "sequence", sequence)
+	return accountNumber, sequence, nil
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// Read a transaction file(json, base64 or hex), the returned builder keeps the messages, fee, memo and signatures
+func readTx(txConfig sdkclient.TxConfig, path string) (sdkclient.TxBuilder, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := client.DecodeTx(txConfig, string(bz), "")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return txConfig.WrapTxBuilder(tx) // https://pkg.go.dev/github.com/cosmos/cosmos-sdk/client#TxConfig
+}
+
+// Write the transaction to path or to stdout when path is empty
+func writeTx(txConfig sdkclient.TxConfig, tx sdk.Tx, path string, encoding string) error {
+	encoded, err := client.EncodeTx(txConfig, tx, encoding)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		fmt.Println(encoded)
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Transaction written to", path)
+	return nil
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"cosmoshub/client"
+	"cosmoshub/client/keyring"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const watchUsage = `usage: submit-transaction watch -address <keys or addresses> [-denoms uatom,...] [-threshold 1atom,...] [flags]
+
+polls the balances of the addresses every -interval, with -subscribe the transfers of the addresses notified by the
+tendermint websocket poll them at once(the first 2 addresses, a node accepts 5 subscriptions). An alert is fired when a balance:
+  change  is different than in the previous poll, disabled with -on-change=false
+  below   is lower than the -threshold of its denom and it was not, or it is at the first poll
+  above   reaches the -threshold of its denom and it was below
+the alerts are printed to stdout as text or JSON lines(-format json) and posted as JSON to the -webhook URL`
+
+// time between polls, a block of the cosmoshub takes ~6s
+const defaultWatchInterval = 6 * time.Second
+
+// timeout of each webhook request
+const webhookTimeout = 10 * time.Second
+
+// A balance alert, the amounts are in the base denom
+type balanceAlert struct {
+	Time      string `json:"time"`
+	Address   string `json:"address"`
+	Denom     string `json:"denom"`
+	Kind      string `json:"kind"` // change, below or above
+	Previous  string `json:"previous,omitempty"`
+	Balance   string `json:"balance"`
+	Threshold string `json:"threshold,omitempty"`
+}
+
+// Polls the balances of the addresses and compares them with the previous poll
+type watcher struct {
+	c          *client.Client
+	addresses  []sdk.AccAddress
+	denoms     []string // all the denoms of the balances when empty
+	thresholds sdk.Coins
+	onChange   bool
+	balances   map[string]sdk.Coins // bech32 address -> balance of the previous poll
+}
+
+// Writes the alerts to stdout and posts them to the webhook
+type alertSink struct {
+	w       io.Writer
+	format  string
+	webhook string
+	denoms  *client.Denoms
+	http    *http.Client
+}
+
+// watch -address <keys or addresses> [-denoms uatom,...] [-threshold 1atom,...] [-interval 6s] [-subscribe] [flags]
+func runWatch(args []string) error {
+	var p params
+	flags := newFlagSet("watch", &p)
+	addresses := flags.String("address", "", "comma separated keys or bech32 addresses to watch")
+	denoms := flags.String("denoms", "", "comma separated denoms to watch, all the denoms of the balances when empty")
+	thresholds := flags.String("threshold", "", "comma separated amounts(ie: 1atom,5000000uosmo), alert when a balance crosses the one of its denom")
+	onChange := flags.Bool("on-change", true, "alert on every balance change, false only alerts the threshold crossings")
+	interval := flags.Duration("interval", defaultWatchInterval, "time between polls")
+	subscribe := flags.Bool("subscribe", false, "poll at once when a transfer of the addresses is notified by the tendermint websocket")
+	format := flags.String("format", "text", "alerts output format: text or json(JSON lines)")
+	webhook := flags.String("webhook", "", "URL where each alert is posted as JSON, ie: http://localhost:8080/alerts")
+	once := flags.Bool("once", false, "poll once, print the alerts and exit")
+	if err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *addresses == "" {
+		return fmt.Errorf("watch: -address is required\n%s", watchUsage)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("watch: unknown -format %q", *format)
+	}
+	if *interval <= 0 {
+		return errors.New("watch: -interval must be positive")
+	}
+	net, err := resolveNetwork(p, flags)
+	if err != nil {
+		return err
+	}
+	kr, err := keyring.New(p.keyringDir)
+	if err != nil {
+		return err
+	}
+	c, err := createClient(net)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	metadata := loadDenoms(c)
+
+	w := &watcher{c: c, denoms: splitList(*denoms), onChange: *onChange}
+	for _, nameOrAddress := range splitList(*addresses) {
+		address, err := resolveAddress(kr, nameOrAddress, net.Bech32)
+		if err != nil {
+			return err
+		}
+		w.addresses = append(w.addresses, address)
+	}
+	for _, threshold := range splitList(*thresholds) {
+		coin, err := metadata.ParseAmount(threshold, net.Denom)
+		if err != nil {
+			return fmt.Errorf("watch: -threshold %s: %w", threshold, err)
+		}
+		w.thresholds = w.thresholds.Add(coin)
+	}
+	sink := alertSink{w: os.Stdout, format: *format, webhook: *webhook, denoms: metadata, http: &http.Client{Timeout: webhookTimeout}}
+
+	// stop watching on ctrl+c
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *once {
+		return w.check(ctx, sink)
+	}
+	var triggers <-chan client.TxEvent
+	if *subscribe {
+		queries, subscribed := transferQueries(w.addresses, net.Bech32)
+		if subscribed < len(w.addresses) {
+			fmt.Fprintln(os.Stderr, "Subscribed to the transfers of the first", subscribed, "addresses, the node accepts",
+				client.DefaultMaxSubscriptions, "subscriptions, the others are polled every", *interval)
+		}
+		triggers, err = c.SubscribeTxs(ctx, queries...)
+		if err != nil {
+			return fmt.Errorf("watch: %w", err)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Watching", len(w.addresses), "addresses every", *interval)
+	return w.run(ctx, *interval, triggers, sink)
+}
+
+// Poll at every interval and when a transaction of the addresses is notified, until ctx is done. A failed poll is
+// printed and retried at the next one.
+func (w *watcher) run(ctx context.Context, interval time.Duration, triggers <-chan client.TxEvent, sink alertSink) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.check(ctx, sink); err != nil {
+			fmt.Fprintln(os.Stderr, "watch error", err)
+		}
+		for waiting := true; waiting; {
+			select {
+			case event, ok := <-triggers:
+				if !ok {
+					// the websocket is closed, only the polling continues
+					triggers = nil
+					continue
+				}
+				waiting = !w.involves(event)
+			case <-ticker.C:
+				waiting = false
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// Poll the balances and fire their alerts
+func (w *watcher) check(ctx context.Context, sink alertSink) error {
+	alerts, err := w.poll(ctx, time.Now())
+	for _, alert := range alerts {
+		if err := sink.fire(ctx, alert); err != nil {
+			fmt.Fprintln(os.Stderr, "alert error", err)
+		}
+	}
+	return err
+}
+
+// Query the balances of the addresses and return the alerts of the differences with the previous poll
+func (w *watcher) poll(ctx context.Context, now time.Time) ([]balanceAlert, error) {
+	var alerts []balanceAlert
+	for _, address := range w.addresses {
+		balance, err := w.balance(ctx, address)
+		if err != nil {
+			return alerts, err
+		}
+		bech32 := w.c.Prefixes().AccAddress(address)
+		previous, polled := w.balances[bech32]
+		alerts = append(alerts, compareBalances(bech32, previous, balance, polled, w.thresholds, w.onChange, now)...)
+		if w.balances == nil {
+			w.balances = map[string]sdk.Coins{}
+		}
+		w.balances[bech32] = balance
+	}
+	return alerts, nil
+}
+
+// The balance of the watched denoms, the threshold denoms are always watched
+func (w *watcher) balance(ctx context.Context, address sdk.AccAddress) (sdk.Coins, error) {
+	if len(w.denoms) == 0 {
+		return w.c.GetAllBalances(ctx, address)
+	}
+	denoms := append([]string{}, w.denoms...)
+	for _, threshold := range w.thresholds {
+		denoms = append(denoms, threshold.Denom)
+	}
+	var coins sdk.Coins
+	queried := map[string]bool{}
+	for _, denom := range denoms {
+		if queried[denom] {
+			continue
+		}
+		queried[denom] = true
+		coin, err := w.c.GetBalance(ctx, address, denom)
+		if err != nil {
+			return nil, err
+		}
+		coins = coins.Add(coin)
+	}
+	return coins, nil
+}
+
+// The queries of the transfers from and to the addresses, two subscriptions per address up to the limit of a node.
+// Returns the queries and the number of addresses subscribed, the first ones.
+func transferQueries(addresses []sdk.AccAddress, prefixes client.Bech32Prefixes) ([]string, int) {
+	var queries []string
+	subscribed := 0
+	for _, address := range addresses {
+		if len(queries)+2 > client.DefaultMaxSubscriptions {
+			break
+		}
+		bech32 := prefixes.AccAddress(address)
+		queries = append(queries,
+			fmt.Sprintf("tm.event = 'Tx' AND transfer.recipient = '%s'", bech32),
+			fmt.Sprintf("tm.event = 'Tx' AND transfer.sender = '%s'", bech32))
+		subscribed++
+	}
+	return queries, subscribed
+}
+
+// Whether the transaction transfers coins from or to a watched address
+func (w *watcher) involves(event client.TxEvent) bool {
+	for _, key := range []string{"transfer.recipient", "transfer.sender"} {
+		for _, value := range event.Events[key] {
+			for _, address := range w.addresses {
+				if value == w.c.Prefixes().AccAddress(address) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// The alerts of the balance of an address: the denoms that changed and the thresholds crossed. At the first poll
+// there is no previous balance, only the balances below their threshold are alerted.
+func compareBalances(address string, previous sdk.Coins, current sdk.Coins, polled bool, thresholds sdk.Coins, onChange bool, now time.Time) []balanceAlert {
+	denoms := map[string]bool{}
+	for _, coins := range []sdk.Coins{previous, current, thresholds} {
+		for _, coin := range coins {
+			denoms[coin.Denom] = true
+		}
+	}
+	var sorted []string
+	for denom := range denoms {
+		sorted = append(sorted, denom)
+	}
+	sort.Strings(sorted)
+
+	var alerts []balanceAlert
+	for _, denom := range sorted {
+		before := sdk.NewCoin(denom, previous.AmountOf(denom))
+		after := sdk.NewCoin(denom, current.AmountOf(denom))
+		alert := balanceAlert{Time: now.UTC().Format(time.RFC3339), Address: address, Denom: denom, Balance: after.String()}
+		if polled {
+			alert.Previous = before.String()
+		}
+		if polled && onChange && !before.IsEqual(after) {
+			changed := alert
+			changed.Kind = "change"
+			alerts = append(alerts, changed)
+		}
+		threshold := thresholds.AmountOf(denom)
+		if !threshold.IsPositive() {
+			continue
+		}
+		alert.Threshold = sdk.NewCoin(denom, threshold).String()
+		switch {
+		case after.Amount.LT(threshold) && (!polled || before.Amount.GTE(threshold)):
+			alert.Kind = "below"
+			alerts = append(alerts, alert)
+		case polled && after.Amount.GTE(threshold) && before.Amount.LT(threshold):
+			alert.Kind = "above"
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// Print the alert and post it to the webhook
+func (s alertSink) fire(ctx context.Context, alert balanceAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	if s.format == "json" {
+		fmt.Fprintln(s.w, string(body))
+	} else {
+		fmt.Fprintln(s.w, s.describe(alert))
+	}
+	if s.webhook == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", s.webhook, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", s.webhook, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: status %s", s.webhook, res.Status)
+	}
+	return nil
+}
+
+// The text of an alert with the amounts in their display denom
+func (s alertSink) describe(alert balanceAlert) string {
+	format := func(amount string) string {
+		coin, err := sdk.ParseCoinNormalized(amount)
+		if err != nil {
+			return amount
+		}
+		return s.denoms.Format(coin)
+	}
+	text := fmt.Sprintf("%s %s %s balance %s", alert.Time, alert.Kind, alert.Address, format(alert.Balance))
+	if alert.Previous != "" {
+		text += " previous " + format(alert.Previous)
+	}
+	if alert.Threshold != "" {
+		text += " threshold " + format(alert.Threshold)
+	}
+	return text
+}
+
+// Poll the balance of address until it has amount, at most timeout. It replaces funding the account by hand and
+// confirming it.
+func waitUntilFunded(c *client.Client, denoms *client.Denoms, address sdk.AccAddress, amount sdk.Coin, interval time.Duration, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	fmt.Printf("Waiting until %s has %s, timeout %s\n", c.Prefixes().AccAddress(address), denoms.Format(amount), timeout)
+	for {
+		balance, err := c.GetBalance(ctx, address, amount.Denom)
+		if err == nil && balance.IsGTE(amount) {
+			fmt.Println("Funded, balance", denoms.Format(balance))
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, "client.GetBalance error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("wait until %s is funded: %w", c.Prefixes().AccAddress(address), ctx.Err())
+		}
+	}
+}
+
+// The non empty items of a comma separated list
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmoshub/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestCompareBalances(t *testing.T) {
+	thresholds := sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000))
+	previous := sdk.NewCoins(sdk.NewInt64Coin("uatom", 1500), sdk.NewInt64Coin("uosmo", 10))
+	current := sdk.NewCoins(sdk.NewInt64Coin("uatom", 900), sdk.NewInt64Coin("uosmo", 10))
+
+	sut := compareBalances("me", previous, current, true, thresholds, true, testNow)
+
+	if len(sut) != 2 {
+		t.Fatalf("compareBalances should alert the change and the crossing of uatom but alerts %+v", sut)
+	}
+	if sut[0].Kind != "change" || sut[0].Previous != "1500uatom" || sut[0].Balance != "900uatom" {
+		t.Errorf("first alert should be the uatom change but is %+v", sut[0])
+	}
+	if sut[1].Kind != "below" || sut[1].Threshold != "1000uatom" || sut[1].Time != "2022-10-01T00:00:00Z" {
+		t.Errorf("second alert should be uatom below 1000uatom but is %+v", sut[1])
+	}
+}
+
+func TestCompareBalancesThresholdOnly(t *testing.T) {
+	thresholds := sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000))
+
+	first := compareBalances("me", nil, nil, false, thresholds, false, testNow)
+	unchanged := compareBalances("me", nil, nil, true, thresholds, false, testNow)
+	above := compareBalances("me", nil, sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000), sdk.NewInt64Coin("uosmo", 5)), true, thresholds, false, testNow)
+
+	if len(first) != 1 || first[0].Kind != "below" || first[0].Balance != "0uatom" || first[0].Previous != "" {
+		t.Errorf("the first poll should alert the empty balance below the threshold but alerts %+v", first)
+	}
+	if len(unchanged) != 0 {
+		t.Errorf("a balance still below the threshold should not be alerted again but alerts %+v", unchanged)
+	}
+	if len(above) != 1 || above[0].Kind != "above" || above[0].Denom != "uatom" {
+		t.Errorf("reaching the threshold should alert above without the uosmo change but alerts %+v", above)
+	}
+}
+
+func TestTransferQueries(t *testing.T) {
+	addresses := []sdk.AccAddress{newTestAccount().address, newTestAccount().address, newTestAccount().address}
+
+	sut, subscribed := transferQueries(addresses, client.CosmosPrefixes)
+
+	if subscribed != 2 || len(sut) != 4 || len(sut) > client.DefaultMaxSubscriptions {
+		t.Fatalf("the first 2 addresses should be subscribed with 4 queries but %d are with %v", subscribed, sut)
+	}
+	first := client.CosmosPrefixes.AccAddress(addresses[0])
+	if sut[0] != "tm.event = 'Tx' AND transfer.recipient = '"+first+"'" || sut[1] != "tm.event = 'Tx' AND transfer.sender = '"+first+"'" {
+		t.Errorf("the transfers to and from the first address should be subscribed but the queries are %v", sut[:2])
+	}
+}
+
+func TestWatcherWebhook(t *testing.T) {
+	node, c := startNode(t)
+	address := sdk.AccAddress([]byte("watched_____________"))
+	node.SetBalance(address, sdk.NewInt64Coin("uatom", 500))
+	var mu sync.Mutex
+	var posted []balanceAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert balanceAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("webhook body error %v", err)
+		}
+		mu.Lock()
+		posted = append(posted, alert)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	var out bytes.Buffer
+	sink := alertSink{w: &out, format: "json", webhook: server.URL, http: server.Client()}
+	sut := &watcher{c: c, addresses: []sdk.AccAddress{address}, denoms: []string{"uatom"}, thresholds: sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000)), onChange: true}
+
+	if err := sut.check(context.Background(), sink); err != nil {
+		t.Fatalf("check error %v", err)
+	}
+	node.SetBalance(address, sdk.NewInt64Coin("uatom", 2000))
+	if err := sut.check(context.Background(), sink); err != nil {
+		t.Fatalf("check error %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 3 || posted[0].Kind != "below" || posted[1].Kind != "change" || posted[2].Kind != "above" {
+		t.Fatalf("the webhook should receive below, change and above but receives %+v", posted)
+	}
+	if posted[2].Address != c.Prefixes().AccAddress(address) || posted[2].Previous != "500uatom" || posted[2].Balance != "2000uatom" {
+		t.Errorf("the above alert should have the address and both balances but is %+v", posted[2])
+	}
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 3 {
+		t.Errorf("the 3 alerts should be printed as JSON lines but there are %d lines: %s", lines, out.String())
+	}
+}
+
+func TestWaitUntilFunded(t *testing.T) {
+	node, c := startNode(t)
+	address := sdk.AccAddress([]byte("funded______________"))
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		node.SetBalance(address, sdk.NewInt64Coin("uatom", 10000))
+	}()
+
+	sut := waitUntilFunded(c, nil, address, sdk.NewInt64Coin("uatom", 10000), time.Millisecond, time.Second)
+
+	if sut != nil {
+		t.Errorf("waitUntilFunded should return when the balance has the amount but returns %v", sut)
+	}
+}
+
+func TestWaitUntilFundedTimeout(t *testing.T) {
+	node, c := startNode(t)
+	address := sdk.AccAddress([]byte("funded______________"))
+	node.SetBalance(address, sdk.NewInt64Coin("uatom", 9999))
+
+	sut := waitUntilFunded(c, nil, address, sdk.NewInt64Coin("uatom", 10000), time.Millisecond, 20*time.Millisecond)
+
+	if !errors.Is(sut, context.DeadlineExceeded) {
+		t.Errorf("waitUntilFunded should time out without the amount but returns %v", sut)
+	}
+}